@@ -0,0 +1,125 @@
+package wavefront
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+)
+
+// ACLPrincipal is an account or group granted access by an ACL
+type ACLPrincipal struct {
+	// ID is the ID of the principal, which is one of a User (their email
+	// address), a ServiceAccount, a UserGroup or a Role
+	ID string `json:"id"`
+
+	// Name is the name of the principal. It is returned by Wavefront and is
+	// ignored when writing ACLs
+	Name string `json:"name,omitempty"`
+}
+
+// UnmarshalJSON is a custom JSON unmarshaller for an ACLPrincipal, which
+// accepts either just the ID or an object with the ID and name
+func (p *ACLPrincipal) UnmarshalJSON(b []byte) error {
+	if err := json.Unmarshal(b, &p.ID); err == nil {
+		return nil
+	}
+	type aclPrincipal ACLPrincipal
+	return json.Unmarshal(b, (*aclPrincipal)(p))
+}
+
+// ACL is the access control list of a single Alert or Dashboard
+type ACL struct {
+	// EntityID is the ID of the Alert or Dashboard
+	EntityID string `json:"entityId"`
+
+	// View are the principals which may view the entity
+	View []ACLPrincipal `json:"viewAcl"`
+
+	// Modify are the principals which may view and modify the entity
+	Modify []ACLPrincipal `json:"modifyAcl"`
+}
+
+// MarshalJSON is a custom JSON marshaller for an ACL. Wavefront accepts only
+// the IDs of the principals, rather than the objects it returns.
+func (a *ACL) MarshalJSON() ([]byte, error) {
+	ids := func(principals []ACLPrincipal) []string {
+		out := make([]string, 0, len(principals))
+		for _, p := range principals {
+			out = append(out, p.ID)
+		}
+		return out
+	}
+	return json.Marshal(&struct {
+		EntityID string   `json:"entityId"`
+		View     []string `json:"viewAcl"`
+		Modify   []string `json:"modifyAcl"`
+	}{
+		EntityID: a.EntityID,
+		View:     ids(a.View),
+		Modify:   ids(a.Modify),
+	})
+}
+
+// getACLs returns the ACLs of the entities with the given IDs, from the ACL
+// endpoint under basePath
+func getACLs(ctx context.Context, client Wavefronter, basePath string, ids []string) ([]*ACL, error) {
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("at least one id must be provided")
+	}
+
+	// the IDs are a repeated query parameter, which params cannot express
+	path := fmt.Sprintf("%s/acl?%s", basePath, url.Values{"id": ids}.Encode())
+	req, err := newRequestWithContext(ctx, client, "GET", path, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Close()
+
+	body, err := ioutil.ReadAll(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	temp := struct {
+		Response []*ACL `json:"response"`
+	}{}
+	if err := json.Unmarshal(body, &temp); err != nil {
+		return nil, err
+	}
+	return temp.Response, nil
+}
+
+// writeACLs sends acls to one of the set, add or remove ACL endpoints under
+// basePath
+func writeACLs(ctx context.Context, client Wavefronter, method, basePath, action string, acls []*ACL) error {
+	if len(acls) == 0 {
+		return fmt.Errorf("at least one acl must be provided")
+	}
+	for _, acl := range acls {
+		if acl.EntityID == "" {
+			return fmt.Errorf("acl entity id field not set")
+		}
+	}
+
+	payload, err := json.Marshal(acls)
+	if err != nil {
+		return err
+	}
+	req, err := newRequestWithContext(ctx, client, method, fmt.Sprintf("%s/acl/%s", basePath, action), nil, payload)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	return resp.Close()
+}
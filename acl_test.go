@@ -0,0 +1,114 @@
+package wavefront
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+type MockACLClient struct {
+	Client
+	method string
+	url    *url.URL
+	body   []byte
+	T      *testing.T
+}
+
+func (m *MockACLClient) Do(req *http.Request) (io.ReadCloser, error) {
+	response, err := ioutil.ReadFile("./fixtures/get-acl-response.json")
+	if err != nil {
+		m.T.Fatal(err)
+	}
+	m.method = req.Method
+	m.url = req.URL
+	m.body = nil
+	if req.Body != nil {
+		m.body, _ = ioutil.ReadAll(req.Body)
+	}
+	return ioutil.NopCloser(bytes.NewReader(response)), nil
+}
+
+func newMockACLClient(t *testing.T) *MockACLClient {
+	return &MockACLClient{
+		Client: Client{
+			Config:     &Config{Token: "1234-5678-9977"},
+			BaseURL:    &url.URL{Scheme: "http", Host: "testing.wavefront.com"},
+			httpClient: http.DefaultClient,
+		},
+		T: t,
+	}
+}
+
+func TestDashboards_GetACL(t *testing.T) {
+	client := newMockACLClient(t)
+	d := &Dashboards{client: client}
+
+	if _, err := d.GetACL(); err == nil {
+		t.Error("expected getting no ACLs to error")
+	}
+
+	acls, err := d.GetACL("dashboard-1", "dashboard-2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if client.url.Path != "/api/v2/dashboard/acl" {
+		t.Errorf("unexpected path %s", client.url.Path)
+	}
+	if ids := client.url.Query()["id"]; len(ids) != 2 || ids[1] != "dashboard-2" {
+		t.Errorf("expected both IDs to be requested, got %v", ids)
+	}
+
+	if len(acls) != 2 || acls[0].EntityID != "dashboard-1" {
+		t.Fatalf("unexpected ACLs %+v", acls)
+	}
+	if len(acls[0].Modify) != 2 || acls[0].Modify[0].ID != "group-2" || acls[0].Modify[0].Name != "Operators" {
+		t.Errorf("unexpected modify ACL %+v", acls[0].Modify)
+	}
+}
+
+func TestAlerts_WriteACL(t *testing.T) {
+	client := newMockACLClient(t)
+	a := &Alerts{client: client}
+
+	if err := a.SetACL(&ACL{}); err == nil {
+		t.Error("expected setting an ACL with no entity ID to error")
+	}
+
+	acl := &ACL{
+		EntityID: "1234",
+		View:     []ACLPrincipal{{ID: "group-1", Name: "Everyone"}},
+		Modify:   []ACLPrincipal{{ID: "group-2"}},
+	}
+	for action, test := range map[string]struct {
+		method string
+		f      func(...*ACL) error
+	}{
+		"set":    {"PUT", a.SetACL},
+		"add":    {"POST", a.AddACL},
+		"remove": {"POST", a.RemoveACL},
+	} {
+		if err := test.f(acl); err != nil {
+			t.Fatal(err)
+		}
+		if client.method != test.method || client.url.Path != "/api/v2/alert/acl/"+action {
+			t.Errorf("%s: unexpected request %s %s", action, client.method, client.url.Path)
+		}
+
+		// principals are sent as IDs
+		var sent []struct {
+			EntityID string   `json:"entityId"`
+			View     []string `json:"viewAcl"`
+			Modify   []string `json:"modifyAcl"`
+		}
+		if err := json.Unmarshal(client.body, &sent); err != nil {
+			t.Fatalf("%s: %s", action, err)
+		}
+		if len(sent) != 1 || sent[0].EntityID != "1234" || sent[0].View[0] != "group-1" || sent[0].Modify[0] != "group-2" {
+			t.Errorf("%s: unexpected body %s", action, client.body)
+		}
+	}
+}
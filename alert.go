@@ -1,9 +1,12 @@
 package wavefront
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"reflect"
+	"strconv"
 )
 
 const (
@@ -64,11 +67,23 @@ type Alert struct {
 	// Status is the current status of the Alert
 	Status []string `json:"status"`
 
+	// Snoozed is the time, in epoch millis, until which the Alert is snoozed,
+	// or -1 if it is snoozed indefinitely. It is set by Wavefront; use Snooze
+	// and Unsnooze to change it.
+	Snoozed int64 `json:"snoozed,omitempty"`
+
 	// Tags are the tags applied to the Alert
 	Tags []string
 
+	// Deleted is true if the Alert is in the trash
+	Deleted bool `json:"deleted,omitempty"`
+
 	FailingHostLabelPairs       []SourceLabelPair `json:"failingHostLabelPairs,omitempty"`
 	InMaintenanceHostLabelPairs []SourceLabelPair `json:"inMaintenanceHostLabelPairs,omitempty"`
+
+	// unknown holds properties returned by Wavefront that are not modelled
+	// above, so that they survive a Get followed by an Update
+	unknown map[string]json.RawMessage
 }
 
 type SourceLabelPair struct {
@@ -84,6 +99,9 @@ type Alerts struct {
 
 const baseAlertPath = "/api/v2/alert"
 
+// alertFields are the JSON properties modelled by Alert
+var alertFields = jsonFieldNames(reflect.TypeOf(Alert{}), "tags")
+
 // UnmarshalJSON is a custom JSON unmarshaller for an Alert, used in order to
 // populate the Tags field in a more intuitive fashion
 func (a *Alert) UnmarshalJSON(b []byte) error {
@@ -98,12 +116,15 @@ func (a *Alert) UnmarshalJSON(b []byte) error {
 		return err
 	}
 	a.Tags = temp.Tags["customerTags"]
-	return nil
+
+	var err error
+	a.unknown, err = unknownFields(b, alertFields)
+	return err
 }
 
 func (a *Alert) MarshalJSON() ([]byte, error) {
 	type alert Alert
-	return json.Marshal(&struct {
+	b, err := json.Marshal(&struct {
 		Tags map[string][]string `json:"tags"`
 		*alert
 	}{
@@ -112,6 +133,10 @@ func (a *Alert) MarshalJSON() ([]byte, error) {
 		},
 		alert: (*alert)(a),
 	})
+	if err != nil {
+		return nil, err
+	}
+	return withUnknownFields(b, a.unknown)
 }
 
 // Alerts is used to return a client for alert-related operations
@@ -122,19 +147,47 @@ func (c *Client) Alerts() *Alerts {
 // Get is used to retrieve an existing Alert by ID.
 // The ID field must be provided
 func (a Alerts) Get(alert *Alert) error {
+	return a.GetContext(context.Background(), alert)
+}
+
+// GetContext is like Get but carries the given context through the request.
+func (a Alerts) GetContext(ctx context.Context, alert *Alert) error {
 	if *alert.ID == "" {
 		return fmt.Errorf("Alert id field is not set")
 	}
 
-	return a.crudAlert("GET", fmt.Sprintf("%s/%s", baseAlertPath, *alert.ID), alert)
+	return a.crudAlert(ctx, "GET", fmt.Sprintf("%s/%s", baseAlertPath, *alert.ID), alert)
 }
 
 // Find returns all alerts filtered by the given search conditions.
 // If filter is nil, all alerts are returned.
 func (a Alerts) Find(filter []*SearchCondition) ([]*Alert, error) {
+	return a.FindContext(context.Background(), filter)
+}
+
+// FindContext is like Find but carries the given context through every page
+// of the search. Pagination stops as soon as the context is done.
+func (a Alerts) FindContext(ctx context.Context, filter []*SearchCondition) ([]*Alert, error) {
+	return a.find(ctx, filter, false)
+}
+
+// FindDeleted returns all alerts in the trash filtered by the given search
+// conditions. If filter is nil, all alerts in the trash are returned.
+func (a Alerts) FindDeleted(filter []*SearchCondition) ([]*Alert, error) {
+	return a.FindDeletedContext(context.Background(), filter)
+}
+
+// FindDeletedContext is like FindDeleted but carries the given context
+// through every page of the search.
+func (a Alerts) FindDeletedContext(ctx context.Context, filter []*SearchCondition) ([]*Alert, error) {
+	return a.find(ctx, filter, true)
+}
+
+func (a Alerts) find(ctx context.Context, filter []*SearchCondition, deleted bool) ([]*Alert, error) {
 	search := &Search{
-		client: a.client,
-		Type:   "alert",
+		client:  a.client,
+		Type:    "alert",
+		Deleted: deleted,
 		Params: &SearchParams{
 			Conditions: filter,
 		},
@@ -143,7 +196,10 @@ func (a Alerts) Find(filter []*SearchCondition) ([]*Alert, error) {
 	var results []*Alert
 	moreItems := true
 	for moreItems == true {
-		resp, err := search.Execute()
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		resp, err := search.ExecuteContext(ctx)
 		if err != nil {
 			return nil, err
 		}
@@ -163,28 +219,45 @@ func (a Alerts) Find(filter []*SearchCondition) ([]*Alert, error) {
 // Create is used to create an Alert in Wavefront.
 // If successful, the ID field of the alert will be populated.
 func (a Alerts) Create(alert *Alert) error {
-	return a.crudAlert("POST", baseAlertPath, alert)
+	return a.CreateContext(context.Background(), alert)
+}
+
+// CreateContext is like Create but carries the given context through the request.
+func (a Alerts) CreateContext(ctx context.Context, alert *Alert) error {
+	return a.crudAlert(ctx, "POST", baseAlertPath, alert)
 }
 
 // Update is used to update an existing Alert.
 // The ID field of the alert must be populated
 func (a Alerts) Update(alert *Alert) error {
+	return a.UpdateContext(context.Background(), alert)
+}
+
+// UpdateContext is like Update but carries the given context through the request.
+func (a Alerts) UpdateContext(ctx context.Context, alert *Alert) error {
 	if alert.ID == nil {
 		return fmt.Errorf("alert id field not set")
 	}
 
-	return a.crudAlert("PUT", fmt.Sprintf("%s/%s", baseAlertPath, *alert.ID), alert)
+	return a.crudAlert(ctx, "PUT", fmt.Sprintf("%s/%s", baseAlertPath, *alert.ID), alert)
 
 }
 
-// Delete is used to delete an existing Alert.
-// The ID field of the alert must be populated
+// Delete is used to delete an existing Alert. A live alert is moved to the
+// trash, and an alert already in the trash is deleted permanently.
+// The ID field of the alert must be populated, and is reset afterwards; use
+// Trash instead to be able to Undelete the alert.
 func (a Alerts) Delete(alert *Alert) error {
+	return a.DeleteContext(context.Background(), alert)
+}
+
+// DeleteContext is like Delete but carries the given context through the request.
+func (a Alerts) DeleteContext(ctx context.Context, alert *Alert) error {
 	if alert.ID == nil {
 		return fmt.Errorf("alert id field not set")
 	}
 
-	err := a.crudAlert("DELETE", fmt.Sprintf("%s/%s", baseAlertPath, *alert.ID), alert)
+	err := a.crudAlert(ctx, "DELETE", fmt.Sprintf("%s/%s", baseAlertPath, *alert.ID), alert)
 	if err != nil {
 		return err
 	}
@@ -195,12 +268,343 @@ func (a Alerts) Delete(alert *Alert) error {
 
 }
 
-func (a Alerts) crudAlert(method, path string, alert *Alert) error {
+// Trash is used to move an existing Alert to the trash. Unlike Delete, the ID
+// field of the alert is kept, so that it can be restored with Undelete.
+// The ID field of the alert must be populated
+func (a Alerts) Trash(alert *Alert) error {
+	return a.TrashContext(context.Background(), alert)
+}
+
+// TrashContext is like Trash but carries the given context through the request.
+func (a Alerts) TrashContext(ctx context.Context, alert *Alert) error {
+	if alert.ID == nil {
+		return fmt.Errorf("alert id field not set")
+	}
+
+	return a.crudAlert(ctx, "DELETE", fmt.Sprintf("%s/%s", baseAlertPath, *alert.ID), alert)
+}
+
+// Undelete is used to restore an Alert from the trash.
+// The ID field of the alert must be populated
+func (a Alerts) Undelete(alert *Alert) error {
+	return a.UndeleteContext(context.Background(), alert)
+}
+
+// UndeleteContext is like Undelete but carries the given context through the request.
+func (a Alerts) UndeleteContext(ctx context.Context, alert *Alert) error {
+	return a.alertAction(ctx, "POST", "undelete", nil, alert)
+}
+
+// DeletePermanently is used to delete an existing Alert without moving it to
+// the trash, whether or not it is already there. It cannot be undone.
+// The ID field of the alert must be populated, and is reset afterwards.
+func (a Alerts) DeletePermanently(alert *Alert) error {
+	return a.DeletePermanentlyContext(context.Background(), alert)
+}
+
+// DeletePermanentlyContext is like DeletePermanently but carries the given
+// context through the request.
+func (a Alerts) DeletePermanentlyContext(ctx context.Context, alert *Alert) error {
+	if alert.ID == nil {
+		return fmt.Errorf("alert id field not set")
+	}
+
+	err := deletePermanently(ctx, a.client, fmt.Sprintf("%s/%s", baseAlertPath, *alert.ID))
+	if err != nil {
+		return err
+	}
+
+	alert.ID = nil
+	return nil
+}
+
+// Snooze is used to snooze an existing Alert for the given number of seconds,
+// or indefinitely if seconds is 0. The ID field of the alert must be
+// populated, and the alert is updated from the response.
+func (a Alerts) Snooze(alert *Alert, seconds int) error {
+	return a.SnoozeContext(context.Background(), alert, seconds)
+}
+
+// SnoozeContext is like Snooze but carries the given context through the request.
+func (a Alerts) SnoozeContext(ctx context.Context, alert *Alert, seconds int) error {
+	var params *map[string]string
+	if seconds > 0 {
+		params = &map[string]string{"seconds": strconv.Itoa(seconds)}
+	}
+	return a.alertAction(ctx, "POST", "snooze", params, alert)
+}
+
+// Unsnooze is used to unsnooze an existing Alert.
+// The ID field of the alert must be populated
+func (a Alerts) Unsnooze(alert *Alert) error {
+	return a.UnsnoozeContext(context.Background(), alert)
+}
+
+// UnsnoozeContext is like Unsnooze but carries the given context through the request.
+func (a Alerts) UnsnoozeContext(ctx context.Context, alert *Alert) error {
+	return a.alertAction(ctx, "POST", "unsnooze", nil, alert)
+}
+
+// Install is used to install a system Alert.
+// The ID field of the alert must be populated
+func (a Alerts) Install(alert *Alert) error {
+	return a.InstallContext(context.Background(), alert)
+}
+
+// InstallContext is like Install but carries the given context through the request.
+func (a Alerts) InstallContext(ctx context.Context, alert *Alert) error {
+	return a.alertAction(ctx, "POST", "install", nil, alert)
+}
+
+// Uninstall is used to uninstall a system Alert.
+// The ID field of the alert must be populated
+func (a Alerts) Uninstall(alert *Alert) error {
+	return a.UninstallContext(context.Background(), alert)
+}
+
+// UninstallContext is like Uninstall but carries the given context through the request.
+func (a Alerts) UninstallContext(ctx context.Context, alert *Alert) error {
+	return a.alertAction(ctx, "POST", "uninstall", nil, alert)
+}
+
+// AlertResult is the outcome of a bulk operation on a single Alert
+type AlertResult struct {
+	// Alert is the alert operated on, updated from the response if successful
+	Alert *Alert
+
+	// Err is the error operating on Alert, or nil if it succeeded
+	Err error
+}
+
+// SnoozeMatching snoozes every Alert matching the given search conditions for
+// the given number of seconds, or indefinitely if seconds is 0. A result is
+// returned for every matching alert; the error is only set if the search
+// itself fails. filter must not be empty, to avoid snoozing every alert by
+// mistake.
+func (a Alerts) SnoozeMatching(filter []*SearchCondition, seconds int) ([]*AlertResult, error) {
+	return a.SnoozeMatchingContext(context.Background(), filter, seconds)
+}
+
+// SnoozeMatchingContext is like SnoozeMatching but carries the given context
+// through the search and every snooze request.
+func (a Alerts) SnoozeMatchingContext(ctx context.Context, filter []*SearchCondition, seconds int) ([]*AlertResult, error) {
+	if len(filter) == 0 {
+		return nil, fmt.Errorf("no search conditions given")
+	}
+	alerts, err := a.FindContext(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*AlertResult, 0, len(alerts))
+	for _, alert := range alerts {
+		results = append(results, &AlertResult{
+			Alert: alert,
+			Err:   a.SnoozeContext(ctx, alert, seconds),
+		})
+	}
+	return results, nil
+}
+
+// AddTag adds a tag to an existing Alert without updating the rest of it,
+// and adds it to the Tags of alert too.
+// The ID field of the alert must be populated
+func (a Alerts) AddTag(alert *Alert, tag string) error {
+	return a.AddTagContext(context.Background(), alert, tag)
+}
+
+// AddTagContext is like AddTag but carries the given context through the request.
+func (a Alerts) AddTagContext(ctx context.Context, alert *Alert, tag string) error {
+	if alert.ID == nil {
+		return fmt.Errorf("alert id field not set")
+	}
+
+	if err := addTag(ctx, a.client, fmt.Sprintf("%s/%s", baseAlertPath, *alert.ID), tag); err != nil {
+		return err
+	}
+	alert.Tags = withTag(alert.Tags, tag)
+	return nil
+}
+
+// RemoveTag removes a tag from an existing Alert without updating the rest of
+// it, and removes it from the Tags of alert too.
+// The ID field of the alert must be populated
+func (a Alerts) RemoveTag(alert *Alert, tag string) error {
+	return a.RemoveTagContext(context.Background(), alert, tag)
+}
+
+// RemoveTagContext is like RemoveTag but carries the given context through the request.
+func (a Alerts) RemoveTagContext(ctx context.Context, alert *Alert, tag string) error {
+	if alert.ID == nil {
+		return fmt.Errorf("alert id field not set")
+	}
+
+	if err := removeTag(ctx, a.client, fmt.Sprintf("%s/%s", baseAlertPath, *alert.ID), tag); err != nil {
+		return err
+	}
+	alert.Tags = withoutTag(alert.Tags, tag)
+	return nil
+}
+
+// SetTags replaces the tags of an existing Alert without updating the rest of
+// it, and sets the Tags of alert too. No tags removes every tag.
+// The ID field of the alert must be populated
+func (a Alerts) SetTags(alert *Alert, tags ...string) error {
+	return a.SetTagsContext(context.Background(), alert, tags...)
+}
+
+// SetTagsContext is like SetTags but carries the given context through the request.
+func (a Alerts) SetTagsContext(ctx context.Context, alert *Alert, tags ...string) error {
+	if alert.ID == nil {
+		return fmt.Errorf("alert id field not set")
+	}
+
+	if err := setTags(ctx, a.client, fmt.Sprintf("%s/%s", baseAlertPath, *alert.ID), tags); err != nil {
+		return err
+	}
+	alert.Tags = tags
+	return nil
+}
+
+// History returns the version history of an existing Alert, newest first.
+// The ID field of the alert must be populated
+func (a Alerts) History(alert *Alert) ([]*HistoryEntry, error) {
+	return a.HistoryContext(context.Background(), alert)
+}
+
+// HistoryContext is like History but carries the given context through every
+// page of the history.
+func (a Alerts) HistoryContext(ctx context.Context, alert *Alert) ([]*HistoryEntry, error) {
+	if alert.ID == nil {
+		return nil, fmt.Errorf("alert id field not set")
+	}
+
+	return listHistory(ctx, a.client, fmt.Sprintf("%s/%s/history", baseAlertPath, *alert.ID))
+}
+
+// GetVersion replaces alert with the given historical version of it.
+// The ID field of the alert must be populated
+func (a Alerts) GetVersion(alert *Alert, version int64) error {
+	return a.GetVersionContext(context.Background(), alert, version)
+}
+
+// GetVersionContext is like GetVersion but carries the given context through the request.
+func (a Alerts) GetVersionContext(ctx context.Context, alert *Alert, version int64) error {
+	return a.alertAction(ctx, "GET", fmt.Sprintf("%d", version), nil, alert)
+}
+
+// Revert reverts an existing Alert to the given historical version by
+// updating it with that version, which creates a new version, and updates
+// alert from the response.
+// The ID field of the alert must be populated
+func (a Alerts) Revert(alert *Alert, version int64) error {
+	return a.RevertContext(context.Background(), alert, version)
+}
+
+// RevertContext is like Revert but carries the given context through both requests.
+func (a Alerts) RevertContext(ctx context.Context, alert *Alert, version int64) error {
+	if alert.ID == nil {
+		return fmt.Errorf("alert id field not set")
+	}
+
+	previous := &Alert{ID: alert.ID}
+	if err := a.GetVersionContext(ctx, previous, version); err != nil {
+		return err
+	}
+	// the ID is not guaranteed to be part of a historical version
+	previous.ID = alert.ID
+	if err := a.UpdateContext(ctx, previous); err != nil {
+		return err
+	}
+	*alert = *previous
+	return nil
+}
+
+// GetACL returns the access control lists of the Alerts with the given IDs
+func (a Alerts) GetACL(ids ...string) ([]*ACL, error) {
+	return a.GetACLContext(context.Background(), ids...)
+}
+
+// GetACLContext is like GetACL but carries the given context through the request.
+func (a Alerts) GetACLContext(ctx context.Context, ids ...string) ([]*ACL, error) {
+	return getACLs(ctx, a.client, baseAlertPath, ids)
+}
+
+// SetACL replaces the access control lists of alerts, one per ACL.
+// Principals left out of an ACL lose their access to its alert
+func (a Alerts) SetACL(acls ...*ACL) error {
+	return a.SetACLContext(context.Background(), acls...)
+}
+
+// SetACLContext is like SetACL but carries the given context through the request.
+func (a Alerts) SetACLContext(ctx context.Context, acls ...*ACL) error {
+	return writeACLs(ctx, a.client, "PUT", baseAlertPath, "set", acls)
+}
+
+// AddACL grants the principals in each ACL access to its alert, keeping
+// any access already granted
+func (a Alerts) AddACL(acls ...*ACL) error {
+	return a.AddACLContext(context.Background(), acls...)
+}
+
+// AddACLContext is like AddACL but carries the given context through the request.
+func (a Alerts) AddACLContext(ctx context.Context, acls ...*ACL) error {
+	return writeACLs(ctx, a.client, "POST", baseAlertPath, "add", acls)
+}
+
+// RemoveACL revokes the access of the principals in each ACL to its alert
+func (a Alerts) RemoveACL(acls ...*ACL) error {
+	return a.RemoveACLContext(context.Background(), acls...)
+}
+
+// RemoveACLContext is like RemoveACL but carries the given context through the request.
+func (a Alerts) RemoveACLContext(ctx context.Context, acls ...*ACL) error {
+	return writeACLs(ctx, a.client, "POST", baseAlertPath, "remove", acls)
+}
+
+// alertAction sends a request to a sub-resource of an Alert, such as snooze,
+// and replaces alert with the Alert in the response
+func (a Alerts) alertAction(ctx context.Context, method, action string, params *map[string]string, alert *Alert) error {
+	if alert.ID == nil {
+		return fmt.Errorf("alert id field not set")
+	}
+
+	req, err := newRequestWithContext(ctx, a.client, method, fmt.Sprintf("%s/%s/%s", baseAlertPath, *alert.ID, action), params, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Close()
+
+	body, err := ioutil.ReadAll(resp)
+	if err != nil {
+		return err
+	}
+
+	temp := struct {
+		Response *Alert `json:"response"`
+	}{}
+	if err := json.Unmarshal(body, &temp); err != nil {
+		return err
+	}
+	// fields such as Snoozed are left out once cleared, so the alert is
+	// replaced rather than merged
+	if temp.Response != nil {
+		*alert = *temp.Response
+	}
+	return nil
+}
+
+func (a Alerts) crudAlert(ctx context.Context, method, path string, alert *Alert) error {
 	payload, err := json.Marshal(alert)
 	if err != nil {
 		return err
 	}
-	req, err := a.client.NewRequest(method, path, nil, payload)
+	req, err := newRequestWithContext(ctx, a.client, method, path, nil, payload)
 	if err != nil {
 		return err
 	}
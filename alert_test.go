@@ -2,6 +2,7 @@ package wavefront
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -73,6 +74,32 @@ func TestAlerts_PaginatedFind(t *testing.T) {
 
 }
 
+func TestAlerts_FindContextCancelled(t *testing.T) {
+	baseurl, _ := url.Parse("http://testing.wavefront.com")
+	a := &Alerts{
+		client: &MockAlertClient{
+			Client: Client{
+				Config:     &Config{Token: "1234-5678-9977"},
+				BaseURL:    baseurl,
+				httpClient: http.DefaultClient,
+				debug:      true,
+			},
+			T: t,
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := a.FindContext(ctx, nil); err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	invoked := ((a.client).(*MockAlertClient)).InvokedCount
+	if invoked != 0 {
+		t.Errorf("paginated search with cancelled context, expected 0 requests, got %d", invoked)
+	}
+}
+
 func (m *MockCrudAlertClient) Do(req *http.Request) (io.ReadCloser, error) {
 	response, err := ioutil.ReadFile("./fixtures/create-alert-response.json")
 	if err != nil {
@@ -203,3 +230,82 @@ func TestMultiThresholdAlerts_CreateUpdateDeleteAlert(t *testing.T) {
 	}
 
 }
+
+type MockActionAlertClient struct {
+	Client
+	path   string
+	params url.Values
+	T      *testing.T
+}
+
+func (m *MockActionAlertClient) Do(req *http.Request) (io.ReadCloser, error) {
+	response, err := ioutil.ReadFile("./fixtures/snooze-alert-response.json")
+	if err != nil {
+		m.T.Fatal(err)
+	}
+	if req.Method != "POST" {
+		m.T.Errorf("request method expected 'POST' got '%s'", req.Method)
+	}
+	m.path = req.URL.Path
+	m.params = req.URL.Query()
+	return ioutil.NopCloser(bytes.NewReader(response)), nil
+}
+
+func TestAlerts_Snooze(t *testing.T) {
+	baseurl, _ := url.Parse("http://testing.wavefront.com")
+	client := &MockActionAlertClient{
+		Client: Client{
+			Config:     &Config{Token: "1234-5678-9977"},
+			BaseURL:    baseurl,
+			httpClient: http.DefaultClient,
+		},
+		T: t,
+	}
+	a := &Alerts{client: client}
+
+	if err := a.Snooze(&Alert{}, 60); err == nil {
+		t.Errorf("expected alert snooze to error with no ID")
+	}
+
+	id := "1234"
+	alert := &Alert{ID: &id, Name: "stale name"}
+	if err := a.Snooze(alert, 3600); err != nil {
+		t.Fatal(err)
+	}
+	if client.path != "/api/v2/alert/1234/snooze" {
+		t.Errorf("unexpected path %s", client.path)
+	}
+	if client.params.Get("seconds") != "3600" {
+		t.Errorf("expected seconds=3600, got %s", client.params.Encode())
+	}
+	if alert.Snoozed != 1600740000000 || alert.Name != "test alert" {
+		t.Errorf("expected alert to be updated from the response, got %+v", alert)
+	}
+
+	if err := a.Snooze(alert, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := client.params["seconds"]; ok {
+		t.Errorf("expected no seconds for an indefinite snooze, got %s", client.params.Encode())
+	}
+
+	for action, f := range map[string]func(*Alert) error{
+		"unsnooze":  a.Unsnooze,
+		"install":   a.Install,
+		"uninstall": a.Uninstall,
+	} {
+		if err := f(alert); err != nil {
+			t.Fatal(err)
+		}
+		if client.path != "/api/v2/alert/1234/"+action {
+			t.Errorf("%s: unexpected path %s", action, client.path)
+		}
+	}
+}
+
+func TestAlerts_SnoozeMatchingRequiresFilter(t *testing.T) {
+	a := &Alerts{client: &MockActionAlertClient{T: t}}
+	if _, err := a.SnoozeMatching(nil, 60); err == nil {
+		t.Error("expected an error snoozing with no search conditions")
+	}
+}
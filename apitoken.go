@@ -0,0 +1,260 @@
+package wavefront
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+)
+
+// The account types an APIToken can belong to
+const (
+	APITokenUserAccount    = "USER_ACCOUNT"
+	APITokenServiceAccount = "SERVICE_ACCOUNT"
+)
+
+// APIToken represents a single Wavefront API token
+type APIToken struct {
+	// ID is the token itself, which is used to authenticate requests. It
+	// should be treated as a secret.
+	ID *string `json:"tokenID,omitempty"`
+
+	// Name is the name given to the token
+	Name string `json:"tokenName,omitempty"`
+
+	// Account is the ID of the account the token belongs to
+	Account string `json:"account,omitempty"`
+
+	// AccountType is the type of account the token belongs to, one of the
+	// APIToken...Account constants
+	AccountType string `json:"accountType,omitempty"`
+
+	DateGenerated int64 `json:"dateGenerated,omitempty"`
+	LastUsed      int64 `json:"lastUsed,omitempty"`
+}
+
+// APITokens is used to perform API token-related operations against the
+// Wavefront API
+type APITokens struct {
+	// client is the Wavefront client used to perform API token-related operations
+	client Wavefronter
+}
+
+const baseAPITokenPath = "/api/v2/apitoken"
+
+// APITokens is used to return a client for API token-related operations
+func (c *Client) APITokens() *APITokens {
+	return &APITokens{client: c}
+}
+
+// List returns the API tokens of the account the client authenticates as
+func (t APITokens) List() ([]*APIToken, error) {
+	return t.ListContext(context.Background())
+}
+
+// ListContext is like List but carries the given context through the request.
+func (t APITokens) ListContext(ctx context.Context) ([]*APIToken, error) {
+	return t.tokenList(ctx, "GET", baseAPITokenPath, nil)
+}
+
+// ListForServiceAccount returns the API tokens of the ServiceAccount with
+// the given ID
+func (t APITokens) ListForServiceAccount(accountID string) ([]*APIToken, error) {
+	return t.ListForServiceAccountContext(context.Background(), accountID)
+}
+
+// ListForServiceAccountContext is like ListForServiceAccount but carries the
+// given context through the request.
+func (t APITokens) ListForServiceAccountContext(ctx context.Context, accountID string) ([]*APIToken, error) {
+	if accountID == "" {
+		return nil, fmt.Errorf("service account id must be provided")
+	}
+	return t.tokenList(ctx, "GET", fmt.Sprintf("%s/serviceaccount/%s", baseAPITokenPath, accountID), nil)
+}
+
+// CreateForServiceAccount is used to generate a new API token with the given
+// name for the ServiceAccount with the given ID
+func (t APITokens) CreateForServiceAccount(accountID, name string) (*APIToken, error) {
+	return t.CreateForServiceAccountContext(context.Background(), accountID, name)
+}
+
+// CreateForServiceAccountContext is like CreateForServiceAccount but carries
+// the given context through every request.
+func (t APITokens) CreateForServiceAccountContext(ctx context.Context, accountID, name string) (*APIToken, error) {
+	if accountID == "" {
+		return nil, fmt.Errorf("service account id must be provided")
+	}
+
+	// Wavefront responds with every token of the account, which can include
+	// other tokens with the same name and generation time, so the new token is
+	// told apart from the tokens the account already has
+	existing, err := t.ListForServiceAccountContext(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[string]bool, len(existing))
+	for _, token := range existing {
+		if token.ID != nil {
+			known[*token.ID] = true
+		}
+	}
+
+	payload, err := json.Marshal(&APIToken{Name: name})
+	if err != nil {
+		return nil, err
+	}
+	tokens, err := t.tokenList(ctx, "POST", fmt.Sprintf("%s/serviceaccount/%s", baseAPITokenPath, accountID), payload)
+	if err != nil {
+		return nil, err
+	}
+	for _, token := range tokens {
+		if token.ID != nil && !known[*token.ID] && token.Name == name {
+			return token, nil
+		}
+	}
+	return nil, fmt.Errorf("created token %q missing from the tokens of %s", name, accountID)
+}
+
+// Rename is used to change the name of an existing APIToken.
+// The ID and AccountType fields of the token must be populated, and the
+// Account field too for the tokens of a service account
+func (t APITokens) Rename(token *APIToken, name string) error {
+	return t.RenameContext(context.Background(), token, name)
+}
+
+// RenameContext is like Rename but carries the given context through the request.
+func (t APITokens) RenameContext(ctx context.Context, token *APIToken, name string) error {
+	path, err := tokenPath(token)
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(&APIToken{ID: token.ID, Name: name})
+	if err != nil {
+		return err
+	}
+	req, err := newRequestWithContext(ctx, t.client, "PUT", path, nil, payload)
+	if err != nil {
+		return err
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Close()
+
+	token.Name = name
+	return nil
+}
+
+// Revoke is used to delete an existing APIToken, so that it can no longer be
+// used. The ID and AccountType fields of the token must be populated, and the
+// Account field too for the tokens of a service account
+func (t APITokens) Revoke(token *APIToken) error {
+	return t.RevokeContext(context.Background(), token)
+}
+
+// RevokeContext is like Revoke but carries the given context through the request.
+func (t APITokens) RevokeContext(ctx context.Context, token *APIToken) error {
+	path, err := tokenPath(token)
+	if err != nil {
+		return err
+	}
+
+	req, err := newRequestWithContext(ctx, t.client, "DELETE", path, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	return resp.Close()
+}
+
+// Rotate replaces an existing service account APIToken. A new token with the
+// same name is generated and passed to store, which should persist it
+// wherever the old token is used, and then the old token is revoked.
+//
+// If store returns an error the new token is revoked and the old token is
+// left in place. If revoking the old token fails, the new token is returned
+// along with the error, as it has already been stored.
+func (t APITokens) Rotate(old *APIToken, store func(*APIToken) error) (*APIToken, error) {
+	return t.RotateContext(context.Background(), old, store)
+}
+
+// RotateContext is like Rotate but carries the given context through every
+// request.
+func (t APITokens) RotateContext(ctx context.Context, old *APIToken, store func(*APIToken) error) (*APIToken, error) {
+	if old.ID == nil || old.AccountType != APITokenServiceAccount || old.Account == "" {
+		return nil, fmt.Errorf("only existing service account tokens can be rotated")
+	}
+
+	token, err := t.CreateForServiceAccountContext(ctx, old.Account, old.Name)
+	if err != nil {
+		return nil, err
+	}
+	if *token.ID == *old.ID {
+		return nil, fmt.Errorf("no new token was generated for %s", old.Account)
+	}
+
+	if err := store(token); err != nil {
+		if revokeErr := t.RevokeContext(ctx, token); revokeErr != nil {
+			return nil, fmt.Errorf("storing new token: %s, and revoking it: %s", err, revokeErr)
+		}
+		return nil, err
+	}
+
+	if err := t.RevokeContext(ctx, old); err != nil {
+		return token, fmt.Errorf("revoking old token: %s", err)
+	}
+	return token, nil
+}
+
+// tokenPath returns the API path of an existing token
+func tokenPath(token *APIToken) (string, error) {
+	if token.ID == nil || *token.ID == "" {
+		return "", fmt.Errorf("api token id field not set")
+	}
+
+	switch token.AccountType {
+	case APITokenUserAccount:
+		return fmt.Sprintf("%s/%s", baseAPITokenPath, *token.ID), nil
+	case APITokenServiceAccount:
+		if token.Account == "" {
+			return "", fmt.Errorf("api token account field not set")
+		}
+		return fmt.Sprintf("%s/serviceaccount/%s/%s", baseAPITokenPath, token.Account, *token.ID), nil
+	default:
+		return "", fmt.Errorf("unknown api token account type %q", token.AccountType)
+	}
+}
+
+// tokenList performs a request which responds with a list of tokens
+func (t APITokens) tokenList(ctx context.Context, method, path string, payload []byte) ([]*APIToken, error) {
+	req, err := newRequestWithContext(ctx, t.client, method, path, nil, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Close()
+
+	body, err := ioutil.ReadAll(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	temp := struct {
+		Response []*APIToken `json:"response"`
+	}{}
+	if err := json.Unmarshal(body, &temp); err != nil {
+		return nil, err
+	}
+	return temp.Response, nil
+}
@@ -0,0 +1,189 @@
+package wavefront
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+type MockAPITokenClient struct {
+	Client
+	requests []string
+	fail     string
+	// fixtures maps requests to the fixtures they respond with, which
+	// otherwise is list-apitoken-response.json
+	fixtures map[string]string
+	T        *testing.T
+}
+
+func (m *MockAPITokenClient) Do(req *http.Request) (io.ReadCloser, error) {
+	request := req.Method + " " + req.URL.Path
+	m.requests = append(m.requests, request)
+	if request == m.fail {
+		return nil, fmt.Errorf("failed to %s", request)
+	}
+	fixture, ok := m.fixtures[request]
+	if !ok {
+		fixture = "list-apitoken-response.json"
+	}
+	response, err := ioutil.ReadFile("./fixtures/" + fixture)
+	if err != nil {
+		m.T.Fatal(err)
+	}
+	return ioutil.NopCloser(bytes.NewReader(response)), nil
+}
+
+func newMockAPITokens(t *testing.T) (*APITokens, *MockAPITokenClient) {
+	client := &MockAPITokenClient{
+		Client: Client{
+			Config:     &Config{Token: "1234-5678-9977"},
+			BaseURL:    &url.URL{Scheme: "http", Host: "testing.wavefront.com"},
+			httpClient: http.DefaultClient,
+		},
+		fixtures: map[string]string{
+			"GET /api/v2/apitoken/serviceaccount/sa::ci": "list-apitoken-existing-response.json",
+		},
+		T: t,
+	}
+	return &APITokens{client: client}, client
+}
+
+func TestAPITokens_CreateForServiceAccount(t *testing.T) {
+	tokens, client := newMockAPITokens(t)
+
+	token, err := tokens.CreateForServiceAccount("sa::ci", "deploys")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		"GET /api/v2/apitoken/serviceaccount/sa::ci",
+		"POST /api/v2/apitoken/serviceaccount/sa::ci",
+	}
+	if fmt.Sprint(client.requests) != fmt.Sprint(expected) {
+		t.Errorf("requests expected %v, got %v", expected, client.requests)
+	}
+	// the token the account did not have before is the one created
+	if *token.ID != "a1b2c3d4-0000-0000-0000-000000000002" {
+		t.Errorf("unexpected token %s", *token.ID)
+	}
+
+	if _, err := tokens.CreateForServiceAccount("sa::ci", "missing"); err == nil {
+		t.Error("expected an error when the created token is not in the response")
+	}
+
+	// no new token in the response is an error, rather than an existing token
+	client.fixtures["POST /api/v2/apitoken/serviceaccount/sa::ci"] = "list-apitoken-existing-response.json"
+	if _, err := tokens.CreateForServiceAccount("sa::ci", "deploys"); err == nil {
+		t.Error("expected an error when no new token is in the response")
+	}
+}
+
+func TestAPITokens_CreateForServiceAccountTied(t *testing.T) {
+	tokens, client := newMockAPITokens(t)
+	// the new token has the same name and generation time as the existing
+	// one, which is listed last
+	client.fixtures["POST /api/v2/apitoken/serviceaccount/sa::ci"] = "create-apitoken-tied-response.json"
+
+	token, err := tokens.CreateForServiceAccount("sa::ci", "deploys")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *token.ID != "a1b2c3d4-0000-0000-0000-000000000004" {
+		t.Errorf("expected the new token, got %s", *token.ID)
+	}
+}
+
+func TestAPITokens_RenameRevoke(t *testing.T) {
+	tokens, client := newMockAPITokens(t)
+
+	if err := tokens.Revoke(&APIToken{}); err == nil {
+		t.Error("expected revoking a token with no ID to error")
+	}
+
+	id := "abcd"
+	token := &APIToken{ID: &id, AccountType: APITokenUserAccount}
+	if err := tokens.Rename(token, "laptop"); err != nil {
+		t.Fatal(err)
+	}
+	if token.Name != "laptop" {
+		t.Errorf("expected the token to be renamed, got %s", token.Name)
+	}
+	if err := tokens.Revoke(token); err != nil {
+		t.Fatal(err)
+	}
+
+	token.AccountType = APITokenServiceAccount
+	token.Account = "sa::ci"
+	if err := tokens.Revoke(token); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"PUT /api/v2/apitoken/abcd",
+		"DELETE /api/v2/apitoken/abcd",
+		"DELETE /api/v2/apitoken/serviceaccount/sa::ci/abcd",
+	}
+	if fmt.Sprint(client.requests) != fmt.Sprint(expected) {
+		t.Errorf("requests expected %v, got %v", expected, client.requests)
+	}
+}
+
+func TestAPITokens_Rotate(t *testing.T) {
+	id := "a1b2c3d4-0000-0000-0000-000000000001"
+	old := &APIToken{ID: &id, Name: "deploys", Account: "sa::ci", AccountType: APITokenServiceAccount}
+
+	tokens, client := newMockAPITokens(t)
+	var stored *APIToken
+	token, err := tokens.Rotate(old, func(t *APIToken) error {
+		stored = t
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored != token || *token.ID == id {
+		t.Errorf("expected the new token to be stored, got %+v", stored)
+	}
+	if last := client.requests[len(client.requests)-1]; last != "DELETE /api/v2/apitoken/serviceaccount/sa::ci/"+id {
+		t.Errorf("expected the old token to be revoked, got %s", last)
+	}
+
+	// the old token is never taken for the new one, even when tied with it
+	tokens, client = newMockAPITokens(t)
+	client.fixtures["POST /api/v2/apitoken/serviceaccount/sa::ci"] = "create-apitoken-tied-response.json"
+	token, err = tokens.Rotate(old, func(*APIToken) error { return nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *token.ID != "a1b2c3d4-0000-0000-0000-000000000004" {
+		t.Errorf("expected the new token, got %s", *token.ID)
+	}
+
+	// a failure to store revokes the new token rather than the old one
+	tokens, client = newMockAPITokens(t)
+	_, err = tokens.Rotate(old, func(*APIToken) error {
+		return fmt.Errorf("vault unavailable")
+	})
+	if err == nil {
+		t.Fatal("expected an error when the token cannot be stored")
+	}
+	if last := client.requests[len(client.requests)-1]; last != "DELETE /api/v2/apitoken/serviceaccount/sa::ci/a1b2c3d4-0000-0000-0000-000000000002" {
+		t.Errorf("expected the new token to be revoked, got %s", last)
+	}
+
+	// a failure to revoke the old token still returns the stored token
+	tokens, client = newMockAPITokens(t)
+	client.fail = "DELETE /api/v2/apitoken/serviceaccount/sa::ci/" + id
+	token, err = tokens.Rotate(old, func(*APIToken) error { return nil })
+	if err == nil || token == nil {
+		t.Errorf("expected the new token and an error, got %v and %v", token, err)
+	}
+
+	if _, err := tokens.Rotate(&APIToken{ID: &id, AccountType: APITokenUserAccount}, nil); err == nil {
+		t.Error("expected rotating a user token to error")
+	}
+}
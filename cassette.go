@@ -0,0 +1,249 @@
+package wavefront
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// CassetteMode selects whether a Cassette records or replays requests
+type CassetteMode int
+
+const (
+	// CassetteRecord sends requests to Wavefront as normal and records each
+	// request/response pair to the cassette file, overwriting it
+	CassetteRecord CassetteMode = iota + 1
+
+	// CassetteReplay serves responses from the cassette file without any
+	// network access. Requests with no recorded match fail with
+	// ErrCassetteMiss.
+	CassetteReplay
+)
+
+// ErrCassetteMiss is returned, wrapped with the request, when a replayed
+// Cassette has no unused recorded interaction matching a request. Such
+// requests are never retried.
+var ErrCassetteMiss = errors.New("no unused recorded interaction")
+
+// defaultCassetteIgnoreParams are the time-based Query.Execute parameters,
+// which change on every run
+var defaultCassetteIgnoreParams = []string{"s", "e"}
+
+// Cassette configures recording of real API interactions to a file and
+// deterministic replay of them, e.g. in CI.
+type Cassette struct {
+	// Path is the cassette file
+	Path string
+
+	// Mode is CassetteRecord or CassetteReplay
+	Mode CassetteMode
+
+	// IgnoreParams are query parameters left out when matching requests.
+	// Defaults to the query start and end times, "s" and "e".
+	IgnoreParams []string
+
+	// RedactHeaders are extra headers whose values are replaced before
+	// recording, e.g. auth or signing headers added by Config.Middleware,
+	// which wraps the cassette. Credentials and the RedactHeaders of
+	// Config.Logging are always redacted.
+	RedactHeaders []string
+}
+
+// cassetteFile is the on-disk format of a Cassette
+type cassetteFile struct {
+	Interactions []*cassetteInteraction `json:"interactions"`
+}
+
+type cassetteInteraction struct {
+	Request struct {
+		Method string      `json:"method"`
+		URL    string      `json:"url"`
+		Header http.Header `json:"header,omitempty"`
+		Body   string      `json:"body,omitempty"`
+	} `json:"request"`
+	Response struct {
+		StatusCode int         `json:"statusCode"`
+		Header     http.Header `json:"header,omitempty"`
+		Body       string      `json:"body"`
+	} `json:"response"`
+
+	// used marks interactions already served during replay
+	used bool
+}
+
+// key identifies a request for matching during replay: its method, its path
+// and normalised query (without the host, so cassettes work against any
+// cluster), and its body.
+func (c *Cassette) key(method string, u *url.URL, body []byte) string {
+	ignore := c.IgnoreParams
+	if ignore == nil {
+		ignore = defaultCassetteIgnoreParams
+	}
+	q := u.Query()
+	for _, p := range ignore {
+		q.Del(p)
+	}
+	// compact JSON bodies so formatting differences don't matter
+	var compact bytes.Buffer
+	if json.Compact(&compact, body) == nil {
+		body = compact.Bytes()
+	}
+	return method + " " + u.Path + "?" + q.Encode() + "\n" + string(body)
+}
+
+// roundTripper returns the RoundTripper implementing the cassette's mode.
+// In record mode, next is used to send requests, and the headers named in
+// redact are redacted along with the cassette's own RedactHeaders.
+func (c *Cassette) roundTripper(next http.RoundTripper, redact []string) (http.RoundTripper, error) {
+	switch c.Mode {
+	case CassetteRecord:
+		return &cassetteRecorder{
+			cassette: c,
+			next:     next,
+			redact:   append(append([]string{}, c.RedactHeaders...), redact...),
+			file:     &cassetteFile{},
+		}, nil
+	case CassetteReplay:
+		b, err := ioutil.ReadFile(c.Path)
+		if err != nil {
+			return nil, err
+		}
+		file := &cassetteFile{}
+		if err := json.Unmarshal(b, file); err != nil {
+			return nil, fmt.Errorf("invalid cassette %s: %s", c.Path, err)
+		}
+		return &cassetteReplayer{cassette: c, file: file}, nil
+	}
+	return nil, fmt.Errorf("unknown cassette mode %d", c.Mode)
+}
+
+type cassetteRecorder struct {
+	cassette *Cassette
+	next     http.RoundTripper
+	redact   []string
+
+	mu   sync.Mutex
+	file *cassetteFile
+}
+
+func (r *cassetteRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+
+	i := &cassetteInteraction{}
+	i.Request.Method = req.Method
+	i.Request.URL = scrubURL(req.URL)
+	i.Request.Header = redactHeader(req.Header, r.redact)
+	i.Request.Body = string(reqBody)
+	i.Response.StatusCode = resp.StatusCode
+	i.Response.Header = redactHeader(resp.Header, r.redact)
+	i.Response.Body = string(respBody)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.file.Interactions = append(r.file.Interactions, i)
+	// the cassette is rewritten after every interaction, so it is complete
+	// whenever the program stops
+	b, err := json.MarshalIndent(r.file, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(r.cassette.Path, b, 0600); err != nil {
+		return nil, fmt.Errorf("error writing cassette %s: %s", r.cassette.Path, err)
+	}
+	return resp, nil
+}
+
+type cassetteReplayer struct {
+	cassette *Cassette
+
+	mu   sync.Mutex
+	file *cassetteFile
+}
+
+func (r *cassetteReplayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	key := r.cassette.key(req.Method, req.URL, reqBody)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, i := range r.file.Interactions {
+		if i.used {
+			continue
+		}
+		u, err := url.Parse(i.Request.URL)
+		if err != nil || r.cassette.key(i.Request.Method, u, []byte(i.Request.Body)) != key {
+			continue
+		}
+		i.used = true
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", i.Response.StatusCode, http.StatusText(i.Response.StatusCode)),
+			StatusCode:    i.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        i.Response.Header,
+			Body:          ioutil.NopCloser(strings.NewReader(i.Response.Body)),
+			ContentLength: int64(len(i.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("cassette %s: %w for %s %s (body %q)",
+		r.cassette.Path, ErrCassetteMiss, req.Method, req.URL.RequestURI(), reqBody)
+}
+
+// readRequestBody returns the body of req, leaving req able to be sent
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		defer body.Close()
+		return ioutil.ReadAll(body)
+	}
+	b, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(b))
+	return b, nil
+}
+
+// scrubURL returns u as a string without any user info, with query
+// parameters in a stable order
+func scrubURL(u *url.URL) string {
+	c := *u
+	c.User = nil
+	c.RawQuery = c.Query().Encode()
+	return c.String()
+}
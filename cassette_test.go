@@ -0,0 +1,160 @@
+package wavefront
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCassette_RecordReplay(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"query":"` + r.URL.Query().Get("q") + `","timeseries":[{"label":"cpu","data":[[1,2]]}]}`))
+	}))
+
+	dir, err := ioutil.TempDir("", "wavefront-cassette")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "cassette.json")
+
+	client, err := NewClient(&Config{
+		Address:       strings.TrimLeft(srv.URL, "https://"),
+		Token:         "123456789",
+		SkipTLSVerify: true,
+		Cassette:      &Cassette{Path: path, Mode: CassetteRecord},
+	})
+	if err != nil {
+		t.Fatal("error initiating client:", err)
+	}
+	if _, err := client.NewQuery(NewQueryParams("ts(cpu)")).Execute(); err != nil {
+		t.Fatal("error recording query:", err)
+	}
+	srv.Close()
+
+	recorded, _ := ioutil.ReadFile(path)
+	if strings.Contains(string(recorded), "123456789") {
+		t.Errorf("token was not scrubbed from cassette: %s", recorded)
+	}
+
+	client, err = NewClient(&Config{
+		Address:  "replay.wavefront.com",
+		Token:    "123456789",
+		Cassette: &Cassette{Path: path, Mode: CassetteReplay},
+	})
+	if err != nil {
+		t.Fatal("error initiating client:", err)
+	}
+
+	// start and end times differ from the recording but still match
+	params := NewQueryParams("ts(cpu)")
+	params.StartTime, params.EndTime = "1", "2"
+	resp, err := client.NewQuery(params).Execute()
+	if err != nil {
+		t.Fatal("error replaying query:", err)
+	}
+	if len(resp.TimeSeries) != 1 || resp.TimeSeries[0].Label != "cpu" {
+		t.Errorf("unexpected replayed response: %+v", resp)
+	}
+
+	if _, err := client.NewQuery(NewQueryParams("ts(mem)")).Execute(); !errors.Is(err, ErrCassetteMiss) {
+		t.Errorf("expected unmatched request to fail with ErrCassetteMiss, got %v", err)
+	}
+	if _, err := client.NewQuery(NewQueryParams("ts(cpu)")).Execute(); err == nil {
+		t.Error("expected request beyond the recorded interactions to fail")
+	}
+}
+
+func TestCassette_RedactsMiddlewareHeaders(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"query":"ts(cpu)","timeseries":[]}`))
+	}))
+	defer srv.Close()
+
+	dir, err := ioutil.TempDir("", "wavefront-cassette")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "cassette.json")
+
+	// middleware wraps the cassette, so the headers it adds are recorded
+	sign := func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			req.Header.Set("X-Signature", "signature-secret")
+			req.Header.Set("X-Log-Secret", "log-secret")
+			return next.RoundTrip(req)
+		})
+	}
+	client, err := NewClient(&Config{
+		Address:       strings.TrimLeft(srv.URL, "https://"),
+		Token:         "123456789",
+		SkipTLSVerify: true,
+		Middleware:    []Middleware{sign},
+		Logging: &LogConfig{
+			Logger:        LoggerFunc(func(*RequestLog) {}),
+			RedactHeaders: []string{"X-Log-Secret"},
+		},
+		Cassette: &Cassette{Path: path, Mode: CassetteRecord, RedactHeaders: []string{"X-Signature"}},
+	})
+	if err != nil {
+		t.Fatal("error initiating client:", err)
+	}
+	if _, err := client.NewQuery(NewQueryParams("ts(cpu)")).Execute(); err != nil {
+		t.Fatal("error recording query:", err)
+	}
+
+	recorded, _ := ioutil.ReadFile(path)
+	for _, secret := range []string{"signature-secret", "log-secret"} {
+		if strings.Contains(string(recorded), secret) {
+			t.Errorf("%s was not scrubbed from cassette: %s", secret, recorded)
+		}
+	}
+	if !strings.Contains(string(recorded), "X-Signature") {
+		t.Errorf("expected the redacted header to be recorded: %s", recorded)
+	}
+}
+
+func TestCassette_ReplayMissNotRetried(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wavefront-cassette")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "cassette.json")
+	if err := ioutil.WriteFile(path, []byte(`{"interactions":[]}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	attempts := 0
+	count := func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			attempts++
+			return next.RoundTrip(req)
+		})
+	}
+	client, err := NewClient(&Config{
+		Address:    "replay.wavefront.com",
+		Token:      "123456789",
+		Retry:      &RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Hour},
+		Middleware: []Middleware{count},
+		Cassette:   &Cassette{Path: path, Mode: CassetteReplay},
+	})
+	if err != nil {
+		t.Fatal("error initiating client:", err)
+	}
+
+	if _, err := client.NewQuery(NewQueryParams("ts(cpu)")).Execute(); !errors.Is(err, ErrCassetteMiss) {
+		t.Errorf("expected ErrCassetteMiss, got %v", err)
+	}
+	if attempts != 1 {
+		t.Errorf("expected a replay miss not to be retried, got %d attempts", attempts)
+	}
+}
@@ -4,12 +4,12 @@ package wavefront
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
-	"net/http/httputil"
 	"net/url"
 	"time"
 )
@@ -29,6 +29,10 @@ type Config struct {
 	// Token is an authentication token that will be passed with all requests
 	Token string
 
+	// TokenProvider, if set, is asked for the authentication token on every
+	// request and takes precedence over Token.
+	TokenProvider TokenProvider
+
 	// Timeout exposes the http client timeout
 	// https://golang.org/src/net/http/client.go
 	Timeout time.Duration
@@ -43,6 +47,31 @@ type Config struct {
 	// SkipTLSVerify disables SSL certificate checking and should be used for
 	// testing only
 	SkipTLSVerify bool
+
+	// Transport is the base RoundTripper used to send requests. If nil, a new
+	// *http.Transport is used. TLSClientConfig, HttpProxy and SkipTLSVerify are
+	// applied to a copy of it, so they require an *http.Transport.
+	Transport http.RoundTripper
+
+	// Middleware is an ordered chain wrapped around Transport. The first
+	// Middleware is the outermost, so it sees each request first.
+	Middleware []Middleware
+
+	// Cassette, if set, records every interaction with the API to a file, or
+	// replays interactions from one instead of making network requests.
+	Cassette *Cassette
+
+	// Logging configures logging of every request and response. If nil,
+	// nothing is logged unless Debug is enabled.
+	Logging *LogConfig
+
+	// Retry configures automatic retries of failed requests. If nil, each
+	// request is attempted only once.
+	Retry *RetryPolicy
+
+	// RateLimit configures client-side rate limiting, shared by all services
+	// of the Client. If nil, requests are not limited.
+	RateLimit *RateLimit
 }
 
 // Client is used to generate API requests against the Wavefront API.
@@ -57,8 +86,12 @@ type Client struct {
 	// httpClient is the client that will be used to make requests against the API.
 	httpClient *http.Client
 
-	// debug, if set, will cause all requests to be dumped to the screen before sending.
+	// debug, if set, will cause all requests and responses to be logged to stdout,
+	// unless Config.Logging is set.
 	debug bool
+
+	// limiter, if set, throttles every request made through Do
+	limiter *rateLimiter
 }
 
 // NewClient returns a new Wavefront client according to the given Config
@@ -68,9 +101,9 @@ func NewClient(config *Config) (*Client, error) {
 		return nil, err
 	}
 
-	t := &http.Transport{
-		TLSClientConfig: config.TLSClientConfig,
-		TLSNextProto:    map[string]func(authority string, c *tls.Conn) http.RoundTripper{},
+	t, err := newTransport(config)
+	if err != nil {
+		return nil, err
 	}
 
 	// Add timeout to http client
@@ -85,20 +118,7 @@ func NewClient(config *Config) (*Client, error) {
 		BaseURL:    baseURL,
 		httpClient: h,
 		debug:      false,
-	}
-
-	// ENABLE HTTP Proxy
-	if config.HttpProxy != "" {
-		proxyUrl, _ := url.Parse(config.HttpProxy)
-		t.Proxy = http.ProxyURL(proxyUrl)
-	}
-
-	//For testing ONLY
-	if config.SkipTLSVerify == true {
-		tr := &http.Transport{
-			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-		}
-		c.httpClient.Transport = tr
+		limiter:    newRateLimiter(config.RateLimit),
 	}
 
 	return c, nil
@@ -125,50 +145,120 @@ func (c Client) NewRequest(method, path string, params *map[string]string, body
 		url.RawQuery = q.Encode()
 	}
 
-	req, err := http.NewRequest(method, url.String(), nil)
+	// a bytes.Reader body lets the request be replayed when retrying
+	var reqBody io.Reader
+	if body != nil {
+		reqBody = bytes.NewReader(body)
+	}
+	req, err := http.NewRequest(method, url.String(), reqBody)
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", c.Config.Token))
+	if c.Config.TokenProvider == nil {
+		req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", c.Config.Token))
+	}
 	req.Header.Add("Accept", "application/json")
 	if body != nil {
 		req.Header.Add("Content-Type", "application/json")
-		req.Body = ioutil.NopCloser(bytes.NewReader(body))
 	}
 	return req, nil
 }
 
+// NewRequestWithContext is like NewRequest but the returned request carries
+// the given context, so that its deadline and cancellation apply to Do.
+func (c Client) NewRequestWithContext(ctx context.Context, method, path string, params *map[string]string, body []byte) (*http.Request, error) {
+	return newRequestWithContext(ctx, c, method, path, params, body)
+}
+
+// newRequestWithContext builds a request through any Wavefronter and attaches
+// ctx to it. Entity services use this so that mocked clients keep working.
+func newRequestWithContext(ctx context.Context, c Wavefronter, method, path string, params *map[string]string, body []byte) (*http.Request, error) {
+	req, err := c.NewRequest(method, path, params, body)
+	if err != nil {
+		return nil, err
+	}
+	return req.WithContext(ctx), nil
+}
+
 // Do executes a request against the Wavefront API.
 // The response body is returned if the request is successful, and should
-// be closed by the requester.
+// be closed by the requester. The request is aborted if its context is done.
+// Each attempt waits on the Client's rate limiter, if one is configured.
+// Failed requests are retried according to Config.Retry. If the final response
+// is not successful, the returned error is an *APIError.
+// If a TokenProvider is configured, it sets the Authorization header on each
+// attempt.
 func (c Client) Do(req *http.Request) (io.ReadCloser, error) {
+	// the body must be replayable for the request to be sent more than once
+	replayable := req.Body == nil || req.GetBody != nil
+	maxAttempts := c.Config.Retry.attempts(req.Method)
+	if !replayable {
+		maxAttempts = 1
+	}
+
+	lc := c.logConfig()
+
+	var resp *http.Response
+	var err error
+	sent, reauthorized := false, false
+	for attempt := 1; ; attempt++ {
+		if sent {
+			if err := rewindBody(req); err != nil {
+				return nil, err
+			}
+		}
+
+		token, authErr := c.authorize(req)
+		if authErr != nil {
+			return nil, authErr
+		}
+
+		if err := c.limiter.wait(req); err != nil {
+			return nil, err
+		}
+
+		start := time.Now()
+		resp, err = c.httpClient.Do(req)
+		sent = true
+		if lc != nil {
+			lc.logRequest(req, attempt, resp, err, time.Since(start))
+		}
+
+		if err == nil && resp.StatusCode == http.StatusUnauthorized && replayable && !reauthorized && c.invalidateToken(token) {
+			// a rejected token is refreshed and the request sent once more,
+			// without counting as a retry attempt
+			reauthorized = true
+			discardBody(resp)
+			attempt--
+			continue
+		}
 
-	if c.debug == true {
-		d, err := httputil.DumpRequestOut(req, true)
-		if err != nil {
+		if attempt >= maxAttempts || !c.Config.Retry.retryable(req.Context(), resp, err) {
+			break
+		}
+		wait := c.Config.Retry.backoff(attempt, resp)
+		discardBody(resp)
+		if err := sleepContext(req.Context(), wait); err != nil {
 			return nil, err
 		}
-		fmt.Printf("%s\n", d)
 	}
-	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
 
 	if resp.StatusCode != 200 {
-		body, err := ioutil.ReadAll(resp.Body)
+		// a partially read body is still useful for diagnostics
+		body, _ := ioutil.ReadAll(resp.Body)
 		resp.Body.Close()
-		if err != nil {
-			return nil, fmt.Errorf("server returned %s\n", resp.Status)
-		}
-		return nil, fmt.Errorf("server returned %s\n%s\n", resp.Status, string(body))
+		return nil, newAPIError(resp, body)
 	}
 
 	return resp.Body, nil
 }
 
-// Debug enables dumping http request objects to stdout
+// Debug enables logging of requests and responses, including bodies, to stdout.
+// Credentials are redacted. It has no effect if Config.Logging is set.
 func (c *Client) Debug(enable bool) {
 	c.debug = enable
 }
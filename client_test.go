@@ -1,6 +1,7 @@
 package wavefront
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"fmt"
@@ -224,3 +225,32 @@ func TestClientTLSWithProxy(t *testing.T) {
 		t.Fatal("HttpProxy not preserved")
 	}
 }
+
+func TestClientDoContextCancelled(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	defer srv.Close()
+
+	client, err := NewClient(&Config{
+		Address:       strings.TrimLeft(srv.URL, "https://"),
+		Token:         "123456789",
+		SkipTLSVerify: true,
+	})
+
+	if err != nil {
+		t.Fatal("error initiating client:", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	req, err := client.NewRequestWithContext(ctx, "GET", "test/thing", nil, nil)
+	if err != nil {
+		t.Fatal("error creating request:", err)
+	}
+	if _, err := client.Do(req); err == nil {
+		t.Error("expected request with cancelled context to fail")
+	}
+}
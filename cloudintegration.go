@@ -0,0 +1,619 @@
+package wavefront
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+)
+
+// Cloud Integration services, used as CloudIntegration.Service
+const (
+	CloudIntegrationCloudWatch       = "CLOUDWATCH"
+	CloudIntegrationCloudTrail       = "CLOUDTRAIL"
+	CloudIntegrationEC2              = "EC2"
+	CloudIntegrationGCP              = "GCP"
+	CloudIntegrationGCPBilling       = "GCPBILLING"
+	CloudIntegrationAzure            = "AZURE"
+	CloudIntegrationAzureActivityLog = "AZUREACTIVITYLOG"
+	CloudIntegrationNewRelic         = "NEWRELIC"
+)
+
+// CloudIntegration represents a single Wavefront Cloud Integration, which
+// pulls metrics or events from a cloud provider. Exactly one of the
+// provider configurations should be set, matching Service. Configurations
+// for providers that are not modelled here are kept as they are.
+type CloudIntegration struct {
+	// ID is the Wavefront-assigned ID of an existing Cloud Integration
+	ID *string `json:"id,omitempty"`
+
+	// Name is the name given to the Cloud Integration
+	Name string `json:"name"`
+
+	// Service is the provider service integrated with, e.g. CLOUDWATCH
+	Service string `json:"service"`
+
+	// AdditionalTags are point tags added to every metric ingested
+	AdditionalTags map[string]string `json:"additionalTags,omitempty"`
+
+	// ServiceRefreshRateInMins is how often, in minutes, the service is polled
+	ServiceRefreshRateInMins int `json:"serviceRefreshRateInMins,omitempty"`
+
+	CloudWatch       *CloudWatchConfiguration       `json:"cloudWatch,omitempty"`
+	CloudTrail       *CloudTrailConfiguration       `json:"cloudTrail,omitempty"`
+	EC2              *EC2Configuration              `json:"ec2,omitempty"`
+	GCP              *GCPConfiguration              `json:"gcp,omitempty"`
+	GCPBilling       *GCPBillingConfiguration       `json:"gcpBilling,omitempty"`
+	Azure            *AzureConfiguration            `json:"azure,omitempty"`
+	AzureActivityLog *AzureActivityLogConfiguration `json:"azureActivityLog,omitempty"`
+	NewRelic         *NewRelicConfiguration         `json:"newRelic,omitempty"`
+
+	// Disabled is true if the Cloud Integration has been disabled, either
+	// with Disable or by Wavefront after repeated errors
+	Disabled bool `json:"disabled,omitempty"`
+
+	// Deleted is true if the Cloud Integration is in the trash
+	Deleted bool `json:"deleted,omitempty"`
+
+	// LastError is the last error encountered by the Cloud Integration
+	LastError   string `json:"lastError,omitempty"`
+	LastErrorMs int64  `json:"lastErrorMs,omitempty"`
+
+	LastReceivedDataPointMs int64  `json:"lastReceivedDataPointMs,omitempty"`
+	LastMetricCount         int64  `json:"lastMetricCount,omitempty"`
+	CreatorId               string `json:"creatorId,omitempty"`
+	UpdaterId               string `json:"updaterId,omitempty"`
+	CreatedEpochMillis      int64  `json:"createdEpochMillis,omitempty"`
+	UpdatedEpochMillis      int64  `json:"updatedEpochMillis,omitempty"`
+
+	// unknown holds properties returned by Wavefront that are not modelled
+	// above, so that they survive a Get followed by an Update
+	unknown map[string]json.RawMessage
+}
+
+// AWSBaseCredentials are the credentials Wavefront uses to access AWS
+type AWSBaseCredentials struct {
+	// RoleARN is the ARN of the role Wavefront assumes
+	RoleARN string `json:"roleArn"`
+
+	// ExternalID is the external ID used when assuming the role
+	ExternalID string `json:"externalId"`
+}
+
+// CloudWatchConfiguration configures an AWS CloudWatch integration
+type CloudWatchConfiguration struct {
+	BaseCredentials *AWSBaseCredentials `json:"baseCredentials,omitempty"`
+
+	// Namespaces are the CloudWatch namespaces to fetch. Defaults to all.
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// MetricFilterRegex, if set, restricts the metrics fetched to those matching it
+	MetricFilterRegex string `json:"metricFilterRegex,omitempty"`
+
+	// PointTagFilterRegex, if set, restricts the AWS tags added as point tags
+	// to those matching it
+	PointTagFilterRegex string `json:"pointTagFilterRegex,omitempty"`
+
+	// InstanceSelectionTags, if set, restricts the EC2 instances fetched to
+	// those with any of these tag keys and values
+	InstanceSelectionTags map[string]string `json:"instanceSelectionTags,omitempty"`
+
+	// VolumeSelectionTags, if set, restricts the EBS volumes fetched to those
+	// with any of these tag keys and values
+	VolumeSelectionTags map[string]string `json:"volumeSelectionTags,omitempty"`
+
+	unknown map[string]json.RawMessage
+}
+
+// CloudTrailConfiguration configures an AWS CloudTrail integration
+type CloudTrailConfiguration struct {
+	BaseCredentials *AWSBaseCredentials `json:"baseCredentials,omitempty"`
+
+	// Region is the AWS region of the S3 bucket
+	Region string `json:"region"`
+
+	// BucketName is the S3 bucket the CloudTrail logs are written to
+	BucketName string `json:"bucketName"`
+
+	// Prefix is the common prefix of the log files in the bucket
+	Prefix string `json:"prefix,omitempty"`
+
+	// FilterRule, if set, is an expression restricting the events ingested
+	FilterRule string `json:"filterRule,omitempty"`
+
+	unknown map[string]json.RawMessage
+}
+
+// EC2Configuration configures an AWS EC2 host integration
+type EC2Configuration struct {
+	BaseCredentials *AWSBaseCredentials `json:"baseCredentials,omitempty"`
+
+	// HostNameTags are the EC2 tags used, in order, to name sources
+	HostNameTags []string `json:"hostNameTags,omitempty"`
+
+	unknown map[string]json.RawMessage
+}
+
+// GCPConfiguration configures a Google Cloud Platform integration
+type GCPConfiguration struct {
+	// ProjectID is the GCP project to fetch metrics from
+	ProjectID string `json:"projectId"`
+
+	// GCPJSONKey is the private key of a service account, in JSON. It is
+	// not returned by Wavefront.
+	GCPJSONKey string `json:"gcpJsonKey,omitempty"`
+
+	// CategoriesToFetch are the GCP services to fetch metrics for, e.g. COMPUTE
+	CategoriesToFetch []string `json:"categoriesToFetch,omitempty"`
+
+	// MetricFilterRegex, if set, restricts the metrics fetched to those matching it
+	MetricFilterRegex string `json:"metricFilterRegex,omitempty"`
+
+	unknown map[string]json.RawMessage
+}
+
+// GCPBillingConfiguration configures a Google Cloud Platform billing integration
+type GCPBillingConfiguration struct {
+	// ProjectID is the GCP project to fetch billing data from
+	ProjectID string `json:"projectId"`
+
+	// GCPAPIKey is an API key for the Cloud Billing API
+	GCPAPIKey string `json:"gcpApiKey,omitempty"`
+
+	// GCPJSONKey is the private key of a service account, in JSON. It is
+	// not returned by Wavefront.
+	GCPJSONKey string `json:"gcpJsonKey,omitempty"`
+
+	unknown map[string]json.RawMessage
+}
+
+// AzureBaseCredentials are the credentials Wavefront uses to access Azure
+type AzureBaseCredentials struct {
+	// ClientID is the ID of the application Wavefront authenticates as
+	ClientID string `json:"clientId"`
+
+	// Tenant is the Azure Active Directory tenant of the application
+	Tenant string `json:"tenant"`
+
+	// ClientSecret is the secret of the application. It is not returned by
+	// Wavefront.
+	ClientSecret string `json:"clientSecret,omitempty"`
+}
+
+// AzureConfiguration configures a Microsoft Azure integration
+type AzureConfiguration struct {
+	BaseCredentials *AzureBaseCredentials `json:"baseCredentials,omitempty"`
+
+	// CategoryFilter, if set, restricts the services fetched
+	CategoryFilter []string `json:"categoryFilter,omitempty"`
+
+	// ResourceGroupFilter, if set, restricts the resource groups fetched
+	ResourceGroupFilter []string `json:"resourceGroupFilter,omitempty"`
+
+	// MetricFilterRegex, if set, restricts the metrics fetched to those matching it
+	MetricFilterRegex string `json:"metricFilterRegex,omitempty"`
+
+	unknown map[string]json.RawMessage
+}
+
+// AzureActivityLogConfiguration configures a Microsoft Azure activity log integration
+type AzureActivityLogConfiguration struct {
+	BaseCredentials *AzureBaseCredentials `json:"baseCredentials,omitempty"`
+
+	// CategoryFilter, if set, restricts the activity log categories ingested
+	CategoryFilter []string `json:"categoryFilter,omitempty"`
+
+	unknown map[string]json.RawMessage
+}
+
+// NewRelicConfiguration configures a New Relic integration
+type NewRelicConfiguration struct {
+	// APIKey is the New Relic REST API key. It is not returned by Wavefront.
+	APIKey string `json:"apiKey,omitempty"`
+
+	// AppFilterRegex, if set, restricts the applications fetched to those matching it
+	AppFilterRegex string `json:"appFilterRegex,omitempty"`
+
+	// HostFilterRegex, if set, restricts the hosts fetched to those matching it
+	HostFilterRegex string `json:"hostFilterRegex,omitempty"`
+
+	// NewRelicMetricFilters restrict the metrics fetched for each application
+	NewRelicMetricFilters []NewRelicMetricFilter `json:"newRelicMetricFilters,omitempty"`
+
+	unknown map[string]json.RawMessage
+}
+
+// NewRelicMetricFilter restricts the metrics fetched for a New Relic application
+type NewRelicMetricFilter struct {
+	AppName           string `json:"appName"`
+	MetricFilterRegex string `json:"metricFilterRegex"`
+}
+
+// CloudIntegrations is used to perform cloud integration-related operations
+// against the Wavefront API
+type CloudIntegrations struct {
+	// client is the Wavefront client used to perform cloud integration-related operations
+	client Wavefronter
+}
+
+const baseCloudIntegrationPath = "/api/v2/cloudintegration"
+
+// The JSON properties modelled by CloudIntegration and its provider configurations
+var (
+	cloudIntegrationFields = jsonFieldNames(reflect.TypeOf(CloudIntegration{}))
+	cloudWatchFields       = jsonFieldNames(reflect.TypeOf(CloudWatchConfiguration{}))
+	cloudTrailFields       = jsonFieldNames(reflect.TypeOf(CloudTrailConfiguration{}))
+	ec2Fields              = jsonFieldNames(reflect.TypeOf(EC2Configuration{}))
+	gcpFields              = jsonFieldNames(reflect.TypeOf(GCPConfiguration{}))
+	gcpBillingFields       = jsonFieldNames(reflect.TypeOf(GCPBillingConfiguration{}))
+	azureFields            = jsonFieldNames(reflect.TypeOf(AzureConfiguration{}))
+	azureActivityLogFields = jsonFieldNames(reflect.TypeOf(AzureActivityLogConfiguration{}))
+	newRelicFields         = jsonFieldNames(reflect.TypeOf(NewRelicConfiguration{}))
+)
+
+// UnmarshalJSON is a custom JSON unmarshaller for a CloudIntegration, used in
+// order to keep properties, including the configuration of providers, that
+// are not modelled by CloudIntegration
+func (c *CloudIntegration) UnmarshalJSON(b []byte) (err error) {
+	type cloudIntegration CloudIntegration
+	c.unknown, err = unmarshalKnown(b, (*cloudIntegration)(c), cloudIntegrationFields)
+	return err
+}
+
+func (c *CloudIntegration) MarshalJSON() ([]byte, error) {
+	type cloudIntegration CloudIntegration
+	return marshalKnown((*cloudIntegration)(c), c.unknown)
+}
+
+func (c *CloudWatchConfiguration) UnmarshalJSON(b []byte) (err error) {
+	type config CloudWatchConfiguration
+	c.unknown, err = unmarshalKnown(b, (*config)(c), cloudWatchFields)
+	return err
+}
+
+func (c *CloudWatchConfiguration) MarshalJSON() ([]byte, error) {
+	type config CloudWatchConfiguration
+	return marshalKnown((*config)(c), c.unknown)
+}
+
+func (c *CloudTrailConfiguration) UnmarshalJSON(b []byte) (err error) {
+	type config CloudTrailConfiguration
+	c.unknown, err = unmarshalKnown(b, (*config)(c), cloudTrailFields)
+	return err
+}
+
+func (c *CloudTrailConfiguration) MarshalJSON() ([]byte, error) {
+	type config CloudTrailConfiguration
+	return marshalKnown((*config)(c), c.unknown)
+}
+
+func (c *EC2Configuration) UnmarshalJSON(b []byte) (err error) {
+	type config EC2Configuration
+	c.unknown, err = unmarshalKnown(b, (*config)(c), ec2Fields)
+	return err
+}
+
+func (c *EC2Configuration) MarshalJSON() ([]byte, error) {
+	type config EC2Configuration
+	return marshalKnown((*config)(c), c.unknown)
+}
+
+func (c *GCPConfiguration) UnmarshalJSON(b []byte) (err error) {
+	type config GCPConfiguration
+	c.unknown, err = unmarshalKnown(b, (*config)(c), gcpFields)
+	return err
+}
+
+func (c *GCPConfiguration) MarshalJSON() ([]byte, error) {
+	type config GCPConfiguration
+	return marshalKnown((*config)(c), c.unknown)
+}
+
+func (c *GCPBillingConfiguration) UnmarshalJSON(b []byte) (err error) {
+	type config GCPBillingConfiguration
+	c.unknown, err = unmarshalKnown(b, (*config)(c), gcpBillingFields)
+	return err
+}
+
+func (c *GCPBillingConfiguration) MarshalJSON() ([]byte, error) {
+	type config GCPBillingConfiguration
+	return marshalKnown((*config)(c), c.unknown)
+}
+
+func (c *AzureConfiguration) UnmarshalJSON(b []byte) (err error) {
+	type config AzureConfiguration
+	c.unknown, err = unmarshalKnown(b, (*config)(c), azureFields)
+	return err
+}
+
+func (c *AzureConfiguration) MarshalJSON() ([]byte, error) {
+	type config AzureConfiguration
+	return marshalKnown((*config)(c), c.unknown)
+}
+
+func (c *AzureActivityLogConfiguration) UnmarshalJSON(b []byte) (err error) {
+	type config AzureActivityLogConfiguration
+	c.unknown, err = unmarshalKnown(b, (*config)(c), azureActivityLogFields)
+	return err
+}
+
+func (c *AzureActivityLogConfiguration) MarshalJSON() ([]byte, error) {
+	type config AzureActivityLogConfiguration
+	return marshalKnown((*config)(c), c.unknown)
+}
+
+func (c *NewRelicConfiguration) UnmarshalJSON(b []byte) (err error) {
+	type config NewRelicConfiguration
+	c.unknown, err = unmarshalKnown(b, (*config)(c), newRelicFields)
+	return err
+}
+
+func (c *NewRelicConfiguration) MarshalJSON() ([]byte, error) {
+	type config NewRelicConfiguration
+	return marshalKnown((*config)(c), c.unknown)
+}
+
+// CloudIntegrations is used to return a client for cloud integration-related operations
+func (c *Client) CloudIntegrations() *CloudIntegrations {
+	return &CloudIntegrations{client: c}
+}
+
+// Get is used to retrieve an existing CloudIntegration by ID.
+// The ID field must be provided
+func (ci CloudIntegrations) Get(integration *CloudIntegration) error {
+	return ci.GetContext(context.Background(), integration)
+}
+
+// GetContext is like Get but carries the given context through the request.
+func (ci CloudIntegrations) GetContext(ctx context.Context, integration *CloudIntegration) error {
+	if integration.ID == nil || *integration.ID == "" {
+		return fmt.Errorf("CloudIntegration id field is not set")
+	}
+
+	return ci.crudCloudIntegration(ctx, "GET", fmt.Sprintf("%s/%s", baseCloudIntegrationPath, *integration.ID), integration)
+}
+
+// Find returns all cloud integrations filtered by the given search conditions.
+// If filter is nil, all cloud integrations are returned.
+func (ci CloudIntegrations) Find(filter []*SearchCondition) ([]*CloudIntegration, error) {
+	return ci.FindContext(context.Background(), filter)
+}
+
+// FindContext is like Find but carries the given context through every page
+// of the search. Pagination stops as soon as the context is done.
+func (ci CloudIntegrations) FindContext(ctx context.Context, filter []*SearchCondition) ([]*CloudIntegration, error) {
+	return ci.find(ctx, filter, false)
+}
+
+// FindDeleted returns all cloud integrations in the trash filtered by the
+// given search conditions. If filter is nil, all cloud integrations in the
+// trash are returned.
+func (ci CloudIntegrations) FindDeleted(filter []*SearchCondition) ([]*CloudIntegration, error) {
+	return ci.FindDeletedContext(context.Background(), filter)
+}
+
+// FindDeletedContext is like FindDeleted but carries the given context
+// through every page of the search.
+func (ci CloudIntegrations) FindDeletedContext(ctx context.Context, filter []*SearchCondition) ([]*CloudIntegration, error) {
+	return ci.find(ctx, filter, true)
+}
+
+func (ci CloudIntegrations) find(ctx context.Context, filter []*SearchCondition, deleted bool) ([]*CloudIntegration, error) {
+	search := &Search{
+		client:  ci.client,
+		Type:    "cloudintegration",
+		Deleted: deleted,
+		Params: &SearchParams{
+			Conditions: filter,
+		},
+	}
+
+	var results []*CloudIntegration
+	moreItems := true
+	for moreItems == true {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		resp, err := search.ExecuteContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		var tmpres []*CloudIntegration
+		err = json.Unmarshal(resp.Response.Items, &tmpres)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, tmpres...)
+		moreItems = resp.Response.MoreItems
+		search.Params.Offset = resp.NextOffset
+	}
+
+	return results, nil
+}
+
+// Create is used to create a CloudIntegration in Wavefront.
+// If successful, the ID field of the cloud integration will be populated.
+func (ci CloudIntegrations) Create(integration *CloudIntegration) error {
+	return ci.CreateContext(context.Background(), integration)
+}
+
+// CreateContext is like Create but carries the given context through the request.
+func (ci CloudIntegrations) CreateContext(ctx context.Context, integration *CloudIntegration) error {
+	return ci.crudCloudIntegration(ctx, "POST", baseCloudIntegrationPath, integration)
+}
+
+// Update is used to update an existing CloudIntegration.
+// The ID field of the cloud integration must be populated
+func (ci CloudIntegrations) Update(integration *CloudIntegration) error {
+	return ci.UpdateContext(context.Background(), integration)
+}
+
+// UpdateContext is like Update but carries the given context through the request.
+func (ci CloudIntegrations) UpdateContext(ctx context.Context, integration *CloudIntegration) error {
+	if integration.ID == nil {
+		return fmt.Errorf("cloud integration id field not set")
+	}
+
+	return ci.crudCloudIntegration(ctx, "PUT", fmt.Sprintf("%s/%s", baseCloudIntegrationPath, *integration.ID), integration)
+}
+
+// Delete is used to delete an existing CloudIntegration. A live cloud
+// integration is moved to the trash, and one already in the trash is deleted
+// permanently. The ID field of the cloud integration must be populated, and
+// is reset afterwards; use Trash instead to be able to Undelete it.
+func (ci CloudIntegrations) Delete(integration *CloudIntegration) error {
+	return ci.DeleteContext(context.Background(), integration)
+}
+
+// DeleteContext is like Delete but carries the given context through the request.
+func (ci CloudIntegrations) DeleteContext(ctx context.Context, integration *CloudIntegration) error {
+	if integration.ID == nil {
+		return fmt.Errorf("cloud integration id field not set")
+	}
+
+	err := ci.crudCloudIntegration(ctx, "DELETE", fmt.Sprintf("%s/%s", baseCloudIntegrationPath, *integration.ID), integration)
+	if err != nil {
+		return err
+	}
+
+	//reset the ID field so deletion is not attempted again
+	integration.ID = nil
+	return nil
+}
+
+// Trash is used to move an existing CloudIntegration to the trash. Unlike
+// Delete, the ID field is kept, so that it can be restored with Undelete.
+// The ID field of the cloud integration must be populated
+func (ci CloudIntegrations) Trash(integration *CloudIntegration) error {
+	return ci.TrashContext(context.Background(), integration)
+}
+
+// TrashContext is like Trash but carries the given context through the request.
+func (ci CloudIntegrations) TrashContext(ctx context.Context, integration *CloudIntegration) error {
+	if integration.ID == nil {
+		return fmt.Errorf("cloud integration id field not set")
+	}
+
+	return ci.crudCloudIntegration(ctx, "DELETE", fmt.Sprintf("%s/%s", baseCloudIntegrationPath, *integration.ID), integration)
+}
+
+// Undelete is used to restore a CloudIntegration from the trash.
+// The ID field of the cloud integration must be populated
+func (ci CloudIntegrations) Undelete(integration *CloudIntegration) error {
+	return ci.UndeleteContext(context.Background(), integration)
+}
+
+// UndeleteContext is like Undelete but carries the given context through the request.
+func (ci CloudIntegrations) UndeleteContext(ctx context.Context, integration *CloudIntegration) error {
+	return ci.cloudIntegrationAction(ctx, "undelete", integration)
+}
+
+// DeletePermanently is used to delete an existing CloudIntegration without
+// moving it to the trash, whether or not it is already there. It cannot be
+// undone. The ID field of the cloud integration must be populated, and is
+// reset afterwards.
+func (ci CloudIntegrations) DeletePermanently(integration *CloudIntegration) error {
+	return ci.DeletePermanentlyContext(context.Background(), integration)
+}
+
+// DeletePermanentlyContext is like DeletePermanently but carries the given
+// context through the request.
+func (ci CloudIntegrations) DeletePermanentlyContext(ctx context.Context, integration *CloudIntegration) error {
+	if integration.ID == nil {
+		return fmt.Errorf("cloud integration id field not set")
+	}
+
+	err := deletePermanently(ctx, ci.client, fmt.Sprintf("%s/%s", baseCloudIntegrationPath, *integration.ID))
+	if err != nil {
+		return err
+	}
+
+	integration.ID = nil
+	return nil
+}
+
+// Enable is used to enable a disabled CloudIntegration.
+// The ID field of the cloud integration must be populated
+func (ci CloudIntegrations) Enable(integration *CloudIntegration) error {
+	return ci.EnableContext(context.Background(), integration)
+}
+
+// EnableContext is like Enable but carries the given context through the request.
+func (ci CloudIntegrations) EnableContext(ctx context.Context, integration *CloudIntegration) error {
+	return ci.cloudIntegrationAction(ctx, "enable", integration)
+}
+
+// Disable is used to stop a CloudIntegration fetching data without deleting it.
+// The ID field of the cloud integration must be populated
+func (ci CloudIntegrations) Disable(integration *CloudIntegration) error {
+	return ci.DisableContext(context.Background(), integration)
+}
+
+// DisableContext is like Disable but carries the given context through the request.
+func (ci CloudIntegrations) DisableContext(ctx context.Context, integration *CloudIntegration) error {
+	return ci.cloudIntegrationAction(ctx, "disable", integration)
+}
+
+// cloudIntegrationAction POSTs to an action endpoint of a CloudIntegration,
+// such as enable, and replaces integration with the one in the response
+func (ci CloudIntegrations) cloudIntegrationAction(ctx context.Context, action string, integration *CloudIntegration) error {
+	if integration.ID == nil {
+		return fmt.Errorf("cloud integration id field not set")
+	}
+
+	req, err := newRequestWithContext(ctx, ci.client, "POST", fmt.Sprintf("%s/%s/%s", baseCloudIntegrationPath, *integration.ID, action), nil, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := ci.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Close()
+
+	body, err := ioutil.ReadAll(resp)
+	if err != nil {
+		return err
+	}
+
+	temp := struct {
+		Response *CloudIntegration `json:"response"`
+	}{}
+	if err := json.Unmarshal(body, &temp); err != nil {
+		return err
+	}
+	if temp.Response != nil {
+		*integration = *temp.Response
+	}
+	return nil
+}
+
+func (ci CloudIntegrations) crudCloudIntegration(ctx context.Context, method, path string, integration *CloudIntegration) error {
+	payload, err := json.Marshal(integration)
+	if err != nil {
+		return err
+	}
+	req, err := newRequestWithContext(ctx, ci.client, method, path, nil, payload)
+	if err != nil {
+		return err
+	}
+
+	resp, err := ci.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Close()
+
+	body, err := ioutil.ReadAll(resp)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(body, &struct {
+		Response *CloudIntegration `json:"response"`
+	}{
+		Response: integration,
+	})
+}
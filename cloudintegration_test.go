@@ -0,0 +1,180 @@
+package wavefront
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+type MockCloudIntegrationClient struct {
+	Client
+	InvokedCount int
+	T            *testing.T
+}
+
+type MockCrudCloudIntegrationClient struct {
+	Client
+	method string
+	path   string
+	body   []byte
+	T      *testing.T
+}
+
+func (m *MockCloudIntegrationClient) Do(req *http.Request) (io.ReadCloser, error) {
+	response, err := ioutil.ReadFile(fmt.Sprintf("./fixtures/paginated-cloudintegration-%d.json", m.InvokedCount))
+	if err != nil {
+		m.T.Fatal(err)
+	}
+	if req.URL.Path != "/api/v2/search/cloudintegration" {
+		m.T.Errorf("search path expected /api/v2/search/cloudintegration, got %s", req.URL.Path)
+	}
+	body, _ := ioutil.ReadAll(req.Body)
+	search := SearchParams{}
+	err = json.Unmarshal(body, &search)
+	if err != nil {
+		m.T.Fatal(err)
+	}
+	if search.Offset != search.Limit*m.InvokedCount {
+		m.T.Errorf("offset, expected %d, got %d", search.Limit*m.InvokedCount, search.Offset)
+	}
+	m.InvokedCount++
+	return ioutil.NopCloser(bytes.NewReader(response)), nil
+}
+
+func TestCloudIntegrations_PaginatedFind(t *testing.T) {
+	baseurl, _ := url.Parse("http://testing.wavefront.com")
+	ci := &CloudIntegrations{
+		client: &MockCloudIntegrationClient{
+			Client: Client{
+				Config:     &Config{Token: "1234-5678-9977"},
+				BaseURL:    baseurl,
+				httpClient: http.DefaultClient,
+			},
+			T: t,
+		},
+	}
+	integrations, err := ci.Find(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	invoked := ((ci.client).(*MockCloudIntegrationClient)).InvokedCount
+	if invoked != 2 {
+		t.Errorf("paginated search, expected 2, got %d", invoked)
+	}
+
+	if len(integrations) != 2 || integrations[1].GCP == nil || integrations[1].GCP.ProjectID != "project-1" {
+		t.Errorf("unexpected cloud integrations: %+v", integrations)
+	}
+}
+
+func (m *MockCrudCloudIntegrationClient) Do(req *http.Request) (io.ReadCloser, error) {
+	response, err := ioutil.ReadFile("./fixtures/create-cloudintegration-response.json")
+	if err != nil {
+		m.T.Fatal(err)
+	}
+	if req.Method != m.method {
+		m.T.Errorf("request method expected '%s' got '%s'", m.method, req.Method)
+	}
+	m.path = req.URL.Path
+	m.body = nil
+	if req.Body != nil {
+		m.body, _ = ioutil.ReadAll(req.Body)
+	}
+	return ioutil.NopCloser(bytes.NewReader(response)), nil
+}
+
+func TestCloudIntegrations_CreateUpdateDeleteCloudIntegration(t *testing.T) {
+	baseurl, _ := url.Parse("http://testing.wavefront.com")
+	client := &MockCrudCloudIntegrationClient{
+		Client: Client{
+			Config:     &Config{Token: "1234-5678-9977"},
+			BaseURL:    baseurl,
+			httpClient: http.DefaultClient,
+		},
+		method: "PUT",
+		T:      t,
+	}
+	ci := &CloudIntegrations{client: client}
+
+	integration := CloudIntegration{
+		Name:    "Production CloudWatch",
+		Service: CloudIntegrationCloudWatch,
+		CloudWatch: &CloudWatchConfiguration{
+			BaseCredentials: &AWSBaseCredentials{
+				RoleARN:    "arn:aws:iam::123456789012:role/wavefront",
+				ExternalID: "wavefront-external-id",
+			},
+			Namespaces: []string{"AWS/EC2", "AWS/RDS"},
+		},
+	}
+
+	if err := ci.Update(&integration); err == nil {
+		t.Errorf("expected cloud integration update to error with no ID")
+	}
+
+	client.method = "POST"
+	if err := ci.Create(&integration); err != nil {
+		t.Fatal(err)
+	}
+	if *integration.ID != "1234" {
+		t.Errorf("cloud integration ID expected 1234, got %s", *integration.ID)
+	}
+
+	// the unmodelled CloudWatch thresholdFilter must be sent back on update
+	client.method = "PUT"
+	if err := ci.Update(&integration); err != nil {
+		t.Error(err)
+	}
+	sent := map[string]map[string]interface{}{}
+	json.Unmarshal(client.body, &sent)
+	if _, ok := sent["cloudWatch"]["thresholdFilter"]; !ok {
+		t.Errorf("expected thresholdFilter to be kept, got %s", client.body)
+	}
+
+	client.method = "POST"
+	for action, f := range map[string]func(*CloudIntegration) error{
+		"enable":   ci.Enable,
+		"disable":  ci.Disable,
+		"undelete": ci.Undelete,
+	} {
+		if err := f(&integration); err != nil {
+			t.Fatal(err)
+		}
+		if client.path != "/api/v2/cloudintegration/1234/"+action {
+			t.Errorf("%s: unexpected path %s", action, client.path)
+		}
+	}
+
+	client.method = "DELETE"
+	if err := ci.Delete(&integration); err != nil {
+		t.Error(err)
+	}
+
+	if integration.ID != nil {
+		t.Error("expected cloud integration ID to be reset after deletion")
+	}
+}
+
+func TestCloudIntegration_KeepsUnknownProviders(t *testing.T) {
+	input := `{"name":"dt","service":"DYNATRACE","dynatrace":{"dynatraceAPIToken":"x","environmentID":"abc"}}`
+
+	integration := &CloudIntegration{}
+	if err := json.Unmarshal([]byte(input), integration); err != nil {
+		t.Fatal(err)
+	}
+	out, err := json.Marshal(integration)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := map[string]map[string]interface{}{}
+	json.Unmarshal(out, &got)
+	if got["dynatrace"]["environmentID"] != "abc" {
+		t.Errorf("expected the dynatrace configuration to be kept, got %s", out)
+	}
+}
@@ -0,0 +1,247 @@
+package wavefront
+
+import (
+	"bufio"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultProfile is the profile used by LoadConfig when none is given
+	DefaultProfile = "default"
+
+	// environment variables consulted by LoadConfig
+	envAddress       = "WAVEFRONT_ADDRESS"
+	envToken         = "WAVEFRONT_TOKEN"
+	envTokenFile     = "WAVEFRONT_TOKEN_FILE"
+	envTimeout       = "WAVEFRONT_TIMEOUT"
+	envHttpProxy     = "WAVEFRONT_HTTP_PROXY"
+	envSkipTLSVerify = "WAVEFRONT_SKIP_TLS_VERIFY"
+	envCABundle      = "WAVEFRONT_CA_BUNDLE"
+	envProfile       = "WAVEFRONT_PROFILE"
+	envConfigFile    = "WAVEFRONT_CONFIG_FILE"
+)
+
+// LoadConfig returns a Config for the named profile. Each setting is taken
+// from the first of the following that provides it:
+//
+//  1. the fields of explicit, which may be nil
+//  2. environment variables: WAVEFRONT_ADDRESS, WAVEFRONT_TOKEN,
+//     WAVEFRONT_TOKEN_FILE, WAVEFRONT_TIMEOUT, WAVEFRONT_HTTP_PROXY,
+//     WAVEFRONT_SKIP_TLS_VERIFY and WAVEFRONT_CA_BUNDLE
+//  3. the profile in the config file, ~/.wavefront/config by default or
+//     WAVEFRONT_CONFIG_FILE if set
+//
+// The token and the token file count as a single setting, so for example
+// WAVEFRONT_TOKEN_FILE takes precedence over 'token' in the profile.
+//
+// If profile is empty, WAVEFRONT_PROFILE is used, falling back to "default".
+// The default profile is optional, so a config file without it, or no config
+// file at all, is not an error, but a profile that was asked for must exist.
+// The config file holds one section per profile, for example:
+//
+//	[default]
+//	address = example.wavefront.com
+//	token = xxxx-xxxx-xxxx-xxxx-xxxx
+//
+//	[staging]
+//	address = staging.wavefront.com
+//	token_file = /var/run/secrets/wavefront-token
+//	timeout = 30s
+//	http_proxy = http://proxy.example.com:8080
+//	ca_bundle = /etc/ssl/corp-ca.pem, /etc/ssl/extra-ca.pem
+//	skip_tls_verify = false
+//
+// explicit is not modified.
+func LoadConfig(explicit *Config, profile string) (*Config, error) {
+	config := &Config{}
+	if explicit != nil {
+		*config = *explicit
+	}
+
+	profileRequested := profile != "" || os.Getenv(envProfile) != ""
+	if profile == "" {
+		profile = os.Getenv(envProfile)
+	}
+	if profile == "" {
+		profile = DefaultProfile
+	}
+
+	// the default profile is optional, so that explicit values and the
+	// environment alone can configure a client, but a requested profile must
+	// exist
+	settings := map[string]string{}
+	path, err := configFilePath()
+	if err != nil {
+		if profileRequested {
+			return nil, err
+		}
+		// only used to describe missing settings
+		path = filepath.Join("~", ".wavefront", "config")
+	} else {
+		found, err := readProfile(path, profile)
+		switch {
+		case os.IsNotExist(err) && !profileRequested:
+		case err != nil:
+			return nil, err
+		case found != nil:
+			settings = found
+		case profileRequested:
+			return nil, fmt.Errorf("profile %q not found in %s", profile, path)
+		}
+	}
+
+	lookup := func(env, key string) (string, string) {
+		if v := os.Getenv(env); v != "" {
+			return v, env
+		}
+		return settings[key], fmt.Sprintf("'%s' in profile %q of %s", key, profile, path)
+	}
+
+	if config.Address == "" {
+		config.Address, _ = lookup(envAddress, "address")
+	}
+
+	if config.Token == "" && config.TokenProvider == nil {
+		if v := os.Getenv(envToken); v != "" {
+			config.Token = v
+		} else if file := os.Getenv(envTokenFile); file != "" {
+			config.TokenProvider = NewFileTokenProvider(expandHome(file))
+		} else if settings["token"] != "" {
+			config.Token = settings["token"]
+		} else if file := settings["token_file"]; file != "" {
+			config.TokenProvider = NewFileTokenProvider(expandHome(file))
+		}
+	}
+
+	if config.Timeout == 0 {
+		if v, source := lookup(envTimeout, "timeout"); v != "" {
+			config.Timeout, err = time.ParseDuration(v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid timeout %q from %s: %s", v, source, err)
+			}
+		}
+	}
+
+	if config.HttpProxy == "" {
+		config.HttpProxy, _ = lookup(envHttpProxy, "http_proxy")
+	}
+
+	if !config.SkipTLSVerify {
+		if v, source := lookup(envSkipTLSVerify, "skip_tls_verify"); v != "" {
+			config.SkipTLSVerify, err = strconv.ParseBool(v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid skip_tls_verify %q from %s: %s", v, source, err)
+			}
+		}
+	}
+
+	if config.TLSClientConfig == nil {
+		if v, source := lookup(envCABundle, "ca_bundle"); v != "" {
+			pool, err := loadCABundle(v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid ca_bundle from %s: %s", source, err)
+			}
+			config.TLSClientConfig = &tls.Config{RootCAs: pool}
+		}
+	}
+
+	if config.Address == "" {
+		return nil, fmt.Errorf("Wavefront address not set: set Config.Address, %s, or 'address' in profile %q of %s",
+			envAddress, profile, path)
+	}
+	if config.Token == "" && config.TokenProvider == nil {
+		return nil, fmt.Errorf("Wavefront token not set: set Config.Token, %s, %s, or 'token' or 'token_file' in profile %q of %s",
+			envToken, envTokenFile, profile, path)
+	}
+
+	return config, nil
+}
+
+// configFilePath returns the path of the config file used by LoadConfig
+func configFilePath() (string, error) {
+	if path := os.Getenv(envConfigFile); path != "" {
+		return expandHome(path), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".wavefront", "config"), nil
+}
+
+// readProfile returns the key/value settings of one section of an INI-style
+// config file, or nil if the file has no such section. Lines starting with #
+// or ; are comments.
+func readProfile(path, profile string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var settings map[string]string
+	section := ""
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			if section == profile && settings == nil {
+				settings = map[string]string{}
+			}
+			continue
+		}
+		kv := strings.SplitN(line, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("%s:%d: expected key = value", path, n)
+		}
+		if section == profile {
+			settings[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// loadCABundle reads a comma-separated list of PEM files into a cert pool
+func loadCABundle(paths string) (*x509.CertPool, error) {
+	pool := x509.NewCertPool()
+	for _, path := range strings.Split(paths, ",") {
+		path = expandHome(strings.TrimSpace(path))
+		if path == "" {
+			continue
+		}
+		pem, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", path)
+		}
+	}
+	return pool, nil
+}
+
+func expandHome(path string) string {
+	if !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[2:])
+}
@@ -0,0 +1,175 @@
+package wavefront
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testConfigFile = `
+# wavefront profiles
+[default]
+address = default.wavefront.com
+token = default-token
+
+[staging]
+address = staging.wavefront.com
+token = staging-token
+timeout = 30s
+http_proxy = http://proxy.example.com:8080
+skip_tls_verify = true
+`
+
+// setTestEnv sets environment variables for the duration of a test, returning
+// a func that restores them
+func setTestEnv(t *testing.T, env map[string]string) func() {
+	saved := map[string]string{}
+	for _, k := range []string{envAddress, envToken, envTokenFile, envTimeout, envHttpProxy,
+		envSkipTLSVerify, envCABundle, envProfile, envConfigFile} {
+		saved[k] = os.Getenv(k)
+		os.Unsetenv(k)
+	}
+	for k, v := range env {
+		os.Setenv(k, v)
+	}
+	return func() {
+		for k, v := range saved {
+			os.Setenv(k, v)
+		}
+	}
+}
+
+func writeTestConfig(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "wavefront-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "config")
+	if err := ioutil.WriteFile(path, []byte(testConfigFile), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path, func() { os.RemoveAll(dir) }
+}
+
+func TestLoadConfig_Precedence(t *testing.T) {
+	path, cleanup := writeTestConfig(t)
+	defer cleanup()
+	defer setTestEnv(t, map[string]string{
+		envConfigFile: path,
+		envToken:      "env-token",
+	})()
+
+	config, err := LoadConfig(&Config{Address: "explicit.wavefront.com"}, "staging")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if config.Address != "explicit.wavefront.com" {
+		t.Errorf("address, expected explicit value, got %s", config.Address)
+	}
+	if config.Token != "env-token" {
+		t.Errorf("token, expected environment value, got %s", config.Token)
+	}
+	if config.Timeout != 30*time.Second || config.HttpProxy != "http://proxy.example.com:8080" || !config.SkipTLSVerify {
+		t.Errorf("expected remaining settings from staging profile, got %+v", config)
+	}
+
+	// a token file in the environment takes precedence over the token of
+	// the staging profile
+	tokenFile := filepath.Join(filepath.Dir(path), "token")
+	os.Unsetenv(envToken)
+	os.Setenv(envTokenFile, tokenFile)
+	config, err = LoadConfig(nil, "staging")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Token != "" {
+		t.Errorf("token, expected none in favour of the token file, got %s", config.Token)
+	}
+	if provider, ok := config.TokenProvider.(*FileTokenProvider); !ok || provider.Path != tokenFile {
+		t.Errorf("expected a file token provider for %s, got %#v", tokenFile, config.TokenProvider)
+	}
+}
+
+func TestLoadConfig_DefaultProfile(t *testing.T) {
+	path, cleanup := writeTestConfig(t)
+	defer cleanup()
+	defer setTestEnv(t, map[string]string{envConfigFile: path})()
+
+	config, err := LoadConfig(nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Address != "default.wavefront.com" || config.Token != "default-token" {
+		t.Errorf("expected default profile, got %+v", config)
+	}
+
+	if _, err := LoadConfig(nil, "production"); err == nil {
+		t.Error("expected error for missing profile")
+	}
+}
+
+func TestLoadConfig_EnvironmentOnly(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wavefront-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "config")
+	if err := ioutil.WriteFile(path, []byte("[prod]\naddress = prod.wavefront.com\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	defer setTestEnv(t, map[string]string{
+		envConfigFile: path,
+		envAddress:    "env.wavefront.com",
+		envToken:      "env-token",
+	})()
+
+	// a config file without a default profile is ignored unless a profile is
+	// requested
+	config, err := LoadConfig(nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Address != "env.wavefront.com" || config.Token != "env-token" {
+		t.Errorf("expected settings from the environment, got %+v", config)
+	}
+	if _, err := LoadConfig(nil, DefaultProfile); err == nil {
+		t.Error("expected error for a requested profile missing from the config file")
+	}
+
+	// so is a missing home directory
+	home := os.Getenv("HOME")
+	defer os.Setenv("HOME", home)
+	os.Unsetenv(envConfigFile)
+	os.Unsetenv("HOME")
+	config, err = LoadConfig(nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Address != "env.wavefront.com" || config.Token != "env-token" {
+		t.Errorf("expected settings from the environment, got %+v", config)
+	}
+	os.Setenv(envProfile, "staging")
+	if _, err := LoadConfig(nil, ""); err == nil {
+		t.Error("expected error for a requested profile with no home directory")
+	}
+}
+
+func TestLoadConfig_MissingSetting(t *testing.T) {
+	defer setTestEnv(t, map[string]string{
+		envConfigFile: filepath.Join(os.TempDir(), "wavefront-config-does-not-exist"),
+		envAddress:    "env.wavefront.com",
+	})()
+
+	_, err := LoadConfig(nil, "")
+	if err == nil {
+		t.Fatal("expected error with no token configured")
+	}
+	if !strings.Contains(err.Error(), "token not set") || !strings.Contains(err.Error(), envToken) {
+		t.Errorf("expected error to name the missing token setting, got: %s", err)
+	}
+}
@@ -1,9 +1,11 @@
 package wavefront
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"reflect"
 )
 
 // Dashboard represents a single Wavefront Dashboard
@@ -60,6 +62,10 @@ type Dashboard struct {
 
 	// ParameterDetails sets variables that can be used within queries
 	ParameterDetails map[string]ParameterDetail `json:"parameterDetails"`
+
+	// unknown holds properties returned by Wavefront that are not modelled
+	// above, so that they survive a Get followed by an Update
+	unknown map[string]json.RawMessage
 }
 
 // ParameterDetail represents a parameter to dashboard that can be consumed in queries
@@ -254,6 +260,9 @@ type DashboardLink struct {
 
 const baseDashboardPath = "/api/v2/dashboard"
 
+// dashboardFields are the JSON properties modelled by Dashboard
+var dashboardFields = jsonFieldNames(reflect.TypeOf(Dashboard{}), "tags")
+
 // UnmarshalJSON is a custom JSON unmarshaller for an Dashboard, used in order to
 // populate the Tags field in a more intuitive fashion
 func (a *Dashboard) UnmarshalJSON(b []byte) error {
@@ -271,7 +280,10 @@ func (a *Dashboard) UnmarshalJSON(b []byte) error {
 		return err
 	}
 	a.Tags = temp.Tags.CustomerTags
-	return nil
+
+	var err error
+	a.unknown, err = unknownFields(b, dashboardFields)
+	return err
 }
 
 func (a *Dashboard) MarshalJSON() ([]byte, error) {
@@ -279,13 +291,17 @@ func (a *Dashboard) MarshalJSON() ([]byte, error) {
 		CustomerTags []string `json:"customerTags,omitempty"`
 	}
 	type dashboard Dashboard
-	return json.Marshal(&struct {
+	b, err := json.Marshal(&struct {
 		Tags *tags `json:"tags,omitempty"`
 		*dashboard
 	}{
 		Tags:      &tags{CustomerTags: a.Tags},
 		dashboard: (*dashboard)(a),
 	})
+	if err != nil {
+		return nil, err
+	}
+	return withUnknownFields(b, a.unknown)
 }
 
 // Dashboards is used to return a client for Dashboard-related operations
@@ -296,9 +312,32 @@ func (c *Client) Dashboards() *Dashboards {
 // Find returns all Dashboards filtered by the given search conditions.
 // If filter is nil, all Dashboards are returned.
 func (a Dashboards) Find(filter []*SearchCondition) ([]*Dashboard, error) {
+	return a.FindContext(context.Background(), filter)
+}
+
+// FindContext is like Find but carries the given context through every page
+// of the search. Pagination stops as soon as the context is done.
+func (a Dashboards) FindContext(ctx context.Context, filter []*SearchCondition) ([]*Dashboard, error) {
+	return a.find(ctx, filter, false)
+}
+
+// FindDeleted returns all dashboards in the trash filtered by the given search
+// conditions. If filter is nil, all dashboards in the trash are returned.
+func (a Dashboards) FindDeleted(filter []*SearchCondition) ([]*Dashboard, error) {
+	return a.FindDeletedContext(context.Background(), filter)
+}
+
+// FindDeletedContext is like FindDeleted but carries the given context
+// through every page of the search.
+func (a Dashboards) FindDeletedContext(ctx context.Context, filter []*SearchCondition) ([]*Dashboard, error) {
+	return a.find(ctx, filter, true)
+}
+
+func (a Dashboards) find(ctx context.Context, filter []*SearchCondition, deleted bool) ([]*Dashboard, error) {
 	search := &Search{
-		client: a.client,
-		Type:   "dashboard",
+		client:  a.client,
+		Type:    "dashboard",
+		Deleted: deleted,
 		Params: &SearchParams{
 			Conditions: filter,
 		},
@@ -307,7 +346,10 @@ func (a Dashboards) Find(filter []*SearchCondition) ([]*Dashboard, error) {
 	var results []*Dashboard
 	moreItems := true
 	for moreItems == true {
-		resp, err := search.Execute()
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		resp, err := search.ExecuteContext(ctx)
 		if err != nil {
 			return nil, err
 		}
@@ -327,38 +369,60 @@ func (a Dashboards) Find(filter []*SearchCondition) ([]*Dashboard, error) {
 // Create is used to create an Dashboard in Wavefront.
 // If successful, the ID field of the Dashboard will be populated.
 func (a Dashboards) Create(dashboard *Dashboard) error {
-	return a.crudDashboard("POST", baseDashboardPath, dashboard)
+	return a.CreateContext(context.Background(), dashboard)
+}
+
+// CreateContext is like Create but carries the given context through the request.
+func (a Dashboards) CreateContext(ctx context.Context, dashboard *Dashboard) error {
+	return a.crudDashboard(ctx, "POST", baseDashboardPath, dashboard)
 }
 
 // Update is used to update an existing Dashboard.
 // The ID field of the Dashboard must be populated
 func (a Dashboards) Update(dashboard *Dashboard) error {
+	return a.UpdateContext(context.Background(), dashboard)
+}
+
+// UpdateContext is like Update but carries the given context through the request.
+func (a Dashboards) UpdateContext(ctx context.Context, dashboard *Dashboard) error {
 	if dashboard.ID == "" {
 		return fmt.Errorf("Dashboard id field not set")
 	}
 
-	return a.crudDashboard("PUT", fmt.Sprintf("%s/%s", baseDashboardPath, dashboard.ID), dashboard)
+	return a.crudDashboard(ctx, "PUT", fmt.Sprintf("%s/%s", baseDashboardPath, dashboard.ID), dashboard)
 
 }
 
 // Get is used to retrieve an existing Dashboard by ID.
 // The ID field must be provided
 func (a Dashboards) Get(dashboard *Dashboard) error {
+	return a.GetContext(context.Background(), dashboard)
+}
+
+// GetContext is like Get but carries the given context through the request.
+func (a Dashboards) GetContext(ctx context.Context, dashboard *Dashboard) error {
 	if dashboard.ID == "" {
 		return fmt.Errorf("Dashboard id field is not set")
 	}
 
-	return a.crudDashboard("GET", fmt.Sprintf("%s/%s", baseDashboardPath, dashboard.ID), dashboard)
+	return a.crudDashboard(ctx, "GET", fmt.Sprintf("%s/%s", baseDashboardPath, dashboard.ID), dashboard)
 }
 
-// Delete is used to delete an existing Dashboard.
-// The ID field of the Dashboard must be populated
+// Delete is used to delete an existing Dashboard. A live dashboard is moved to
+// the trash, and a dashboard already in the trash is deleted permanently.
+// The ID field of the Dashboard must be populated, and is reset afterwards;
+// use Trash instead to be able to Undelete the dashboard.
 func (a Dashboards) Delete(dashboard *Dashboard) error {
+	return a.DeleteContext(context.Background(), dashboard)
+}
+
+// DeleteContext is like Delete but carries the given context through the request.
+func (a Dashboards) DeleteContext(ctx context.Context, dashboard *Dashboard) error {
 	if dashboard.ID == "" {
 		return fmt.Errorf("Dashboard id field not set")
 	}
 
-	err := a.crudDashboard("DELETE", fmt.Sprintf("%s/%s", baseDashboardPath, dashboard.ID), dashboard)
+	err := a.crudDashboard(ctx, "DELETE", fmt.Sprintf("%s/%s", baseDashboardPath, dashboard.ID), dashboard)
 	if err != nil {
 		return err
 	}
@@ -369,12 +433,253 @@ func (a Dashboards) Delete(dashboard *Dashboard) error {
 
 }
 
-func (a Dashboards) crudDashboard(method, path string, dashboard *Dashboard) error {
+// Trash is used to move an existing Dashboard to the trash. Unlike Delete, the
+// ID field of the Dashboard is kept, so that it can be restored with Undelete.
+// The ID field of the Dashboard must be populated
+func (a Dashboards) Trash(dashboard *Dashboard) error {
+	return a.TrashContext(context.Background(), dashboard)
+}
+
+// TrashContext is like Trash but carries the given context through the request.
+func (a Dashboards) TrashContext(ctx context.Context, dashboard *Dashboard) error {
+	if dashboard.ID == "" {
+		return fmt.Errorf("Dashboard id field not set")
+	}
+
+	return a.crudDashboard(ctx, "DELETE", fmt.Sprintf("%s/%s", baseDashboardPath, dashboard.ID), dashboard)
+}
+
+// Undelete is used to restore a Dashboard from the trash.
+// The ID field of the Dashboard must be populated
+func (a Dashboards) Undelete(dashboard *Dashboard) error {
+	return a.UndeleteContext(context.Background(), dashboard)
+}
+
+// UndeleteContext is like Undelete but carries the given context through the request.
+func (a Dashboards) UndeleteContext(ctx context.Context, dashboard *Dashboard) error {
+	return a.dashboardAction(ctx, "POST", "undelete", dashboard)
+}
+
+// DeletePermanently is used to delete an existing Dashboard without moving it
+// to the trash, whether or not it is already there. It cannot be undone.
+// The ID field of the Dashboard must be populated, and is reset afterwards.
+func (a Dashboards) DeletePermanently(dashboard *Dashboard) error {
+	return a.DeletePermanentlyContext(context.Background(), dashboard)
+}
+
+// DeletePermanentlyContext is like DeletePermanently but carries the given
+// context through the request.
+func (a Dashboards) DeletePermanentlyContext(ctx context.Context, dashboard *Dashboard) error {
+	if dashboard.ID == "" {
+		return fmt.Errorf("Dashboard id field not set")
+	}
+
+	err := deletePermanently(ctx, a.client, fmt.Sprintf("%s/%s", baseDashboardPath, dashboard.ID))
+	if err != nil {
+		return err
+	}
+
+	dashboard.ID = ""
+	return nil
+}
+
+// AddTag adds a tag to an existing Dashboard without updating the rest of it,
+// and adds it to the Tags of dashboard too.
+// The ID field of the Dashboard must be populated
+func (a Dashboards) AddTag(dashboard *Dashboard, tag string) error {
+	return a.AddTagContext(context.Background(), dashboard, tag)
+}
+
+// AddTagContext is like AddTag but carries the given context through the request.
+func (a Dashboards) AddTagContext(ctx context.Context, dashboard *Dashboard, tag string) error {
+	if dashboard.ID == "" {
+		return fmt.Errorf("Dashboard id field not set")
+	}
+
+	if err := addTag(ctx, a.client, fmt.Sprintf("%s/%s", baseDashboardPath, dashboard.ID), tag); err != nil {
+		return err
+	}
+	dashboard.Tags = withTag(dashboard.Tags, tag)
+	return nil
+}
+
+// RemoveTag removes a tag from an existing Dashboard without updating the rest of
+// it, and removes it from the Tags of dashboard too.
+// The ID field of the Dashboard must be populated
+func (a Dashboards) RemoveTag(dashboard *Dashboard, tag string) error {
+	return a.RemoveTagContext(context.Background(), dashboard, tag)
+}
+
+// RemoveTagContext is like RemoveTag but carries the given context through the request.
+func (a Dashboards) RemoveTagContext(ctx context.Context, dashboard *Dashboard, tag string) error {
+	if dashboard.ID == "" {
+		return fmt.Errorf("Dashboard id field not set")
+	}
+
+	if err := removeTag(ctx, a.client, fmt.Sprintf("%s/%s", baseDashboardPath, dashboard.ID), tag); err != nil {
+		return err
+	}
+	dashboard.Tags = withoutTag(dashboard.Tags, tag)
+	return nil
+}
+
+// SetTags replaces the tags of an existing Dashboard without updating the rest of
+// it, and sets the Tags of dashboard too. No tags removes every tag.
+// The ID field of the Dashboard must be populated
+func (a Dashboards) SetTags(dashboard *Dashboard, tags ...string) error {
+	return a.SetTagsContext(context.Background(), dashboard, tags...)
+}
+
+// SetTagsContext is like SetTags but carries the given context through the request.
+func (a Dashboards) SetTagsContext(ctx context.Context, dashboard *Dashboard, tags ...string) error {
+	if dashboard.ID == "" {
+		return fmt.Errorf("Dashboard id field not set")
+	}
+
+	if err := setTags(ctx, a.client, fmt.Sprintf("%s/%s", baseDashboardPath, dashboard.ID), tags); err != nil {
+		return err
+	}
+	dashboard.Tags = tags
+	return nil
+}
+
+// History returns the version history of an existing Dashboard, newest first.
+// The ID field of the Dashboard must be populated
+func (a Dashboards) History(dashboard *Dashboard) ([]*HistoryEntry, error) {
+	return a.HistoryContext(context.Background(), dashboard)
+}
+
+// HistoryContext is like History but carries the given context through every
+// page of the history.
+func (a Dashboards) HistoryContext(ctx context.Context, dashboard *Dashboard) ([]*HistoryEntry, error) {
+	if dashboard.ID == "" {
+		return nil, fmt.Errorf("Dashboard id field not set")
+	}
+
+	return listHistory(ctx, a.client, fmt.Sprintf("%s/%s/history", baseDashboardPath, dashboard.ID))
+}
+
+// GetVersion replaces dashboard with the given historical version of it.
+// The ID field of the Dashboard must be populated
+func (a Dashboards) GetVersion(dashboard *Dashboard, version int64) error {
+	return a.GetVersionContext(context.Background(), dashboard, version)
+}
+
+// GetVersionContext is like GetVersion but carries the given context through the request.
+func (a Dashboards) GetVersionContext(ctx context.Context, dashboard *Dashboard, version int64) error {
+	return a.dashboardAction(ctx, "GET", fmt.Sprintf("%d", version), dashboard)
+}
+
+// Revert reverts an existing Dashboard to the given historical version by
+// updating it with that version, which creates a new version, and updates
+// dashboard from the response.
+// The ID field of the Dashboard must be populated
+func (a Dashboards) Revert(dashboard *Dashboard, version int64) error {
+	return a.RevertContext(context.Background(), dashboard, version)
+}
+
+// RevertContext is like Revert but carries the given context through both requests.
+func (a Dashboards) RevertContext(ctx context.Context, dashboard *Dashboard, version int64) error {
+	if dashboard.ID == "" {
+		return fmt.Errorf("Dashboard id field not set")
+	}
+
+	previous := &Dashboard{ID: dashboard.ID}
+	if err := a.GetVersionContext(ctx, previous, version); err != nil {
+		return err
+	}
+	// the ID is not guaranteed to be part of a historical version
+	previous.ID = dashboard.ID
+	if err := a.UpdateContext(ctx, previous); err != nil {
+		return err
+	}
+	*dashboard = *previous
+	return nil
+}
+
+// GetACL returns the access control lists of the Dashboards with the given IDs
+func (a Dashboards) GetACL(ids ...string) ([]*ACL, error) {
+	return a.GetACLContext(context.Background(), ids...)
+}
+
+// GetACLContext is like GetACL but carries the given context through the request.
+func (a Dashboards) GetACLContext(ctx context.Context, ids ...string) ([]*ACL, error) {
+	return getACLs(ctx, a.client, baseDashboardPath, ids)
+}
+
+// SetACL replaces the access control lists of dashboards, one per ACL.
+// Principals left out of an ACL lose their access to its dashboard
+func (a Dashboards) SetACL(acls ...*ACL) error {
+	return a.SetACLContext(context.Background(), acls...)
+}
+
+// SetACLContext is like SetACL but carries the given context through the request.
+func (a Dashboards) SetACLContext(ctx context.Context, acls ...*ACL) error {
+	return writeACLs(ctx, a.client, "PUT", baseDashboardPath, "set", acls)
+}
+
+// AddACL grants the principals in each ACL access to its dashboard, keeping
+// any access already granted
+func (a Dashboards) AddACL(acls ...*ACL) error {
+	return a.AddACLContext(context.Background(), acls...)
+}
+
+// AddACLContext is like AddACL but carries the given context through the request.
+func (a Dashboards) AddACLContext(ctx context.Context, acls ...*ACL) error {
+	return writeACLs(ctx, a.client, "POST", baseDashboardPath, "add", acls)
+}
+
+// RemoveACL revokes the access of the principals in each ACL to its dashboard
+func (a Dashboards) RemoveACL(acls ...*ACL) error {
+	return a.RemoveACLContext(context.Background(), acls...)
+}
+
+// RemoveACLContext is like RemoveACL but carries the given context through the request.
+func (a Dashboards) RemoveACLContext(ctx context.Context, acls ...*ACL) error {
+	return writeACLs(ctx, a.client, "POST", baseDashboardPath, "remove", acls)
+}
+
+// dashboardAction sends a request to a sub-resource of a Dashboard, such as a
+// historical version, and replaces dashboard with the Dashboard in the response
+func (a Dashboards) dashboardAction(ctx context.Context, method, action string, dashboard *Dashboard) error {
+	if dashboard.ID == "" {
+		return fmt.Errorf("Dashboard id field not set")
+	}
+
+	req, err := newRequestWithContext(ctx, a.client, method, fmt.Sprintf("%s/%s/%s", baseDashboardPath, dashboard.ID, action), nil, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Close()
+
+	body, err := ioutil.ReadAll(resp)
+	if err != nil {
+		return err
+	}
+
+	temp := struct {
+		Response *Dashboard `json:"response"`
+	}{}
+	if err := json.Unmarshal(body, &temp); err != nil {
+		return err
+	}
+	if temp.Response != nil {
+		*dashboard = *temp.Response
+	}
+	return nil
+}
+
+func (a Dashboards) crudDashboard(ctx context.Context, method, path string, dashboard *Dashboard) error {
 	payload, err := json.Marshal(dashboard)
 	if err != nil {
 		return err
 	}
-	req, err := a.client.NewRequest(method, path, nil, payload)
+	req, err := newRequestWithContext(ctx, a.client, method, path, nil, payload)
 	if err != nil {
 		return err
 	}
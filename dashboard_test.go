@@ -2,6 +2,7 @@ package wavefront
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -14,7 +15,9 @@ import (
 type MockDashboardClient struct {
 	Client
 	InvokedCount int
-	T            *testing.T
+	// afterPage, if set, is called after each page is served
+	afterPage func()
+	T         *testing.T
 }
 
 type MockCrudDashboardClient struct {
@@ -38,6 +41,9 @@ func (m *MockDashboardClient) Do(req *http.Request) (io.ReadCloser, error) {
 		m.T.Errorf("offset, expected %d, got %d", search.Limit*m.InvokedCount, search.Offset)
 	}
 	m.InvokedCount++
+	if m.afterPage != nil {
+		m.afterPage()
+	}
 	return ioutil.NopCloser(bytes.NewReader(response)), nil
 }
 
@@ -73,6 +79,40 @@ func TestDashboards_PaginatedFind(t *testing.T) {
 
 }
 
+func TestDashboards_FindContextCancelled(t *testing.T) {
+	baseurl, _ := url.Parse("http://testing.wavefront.com")
+	client := &MockDashboardClient{
+		Client: Client{
+			Config:     &Config{Token: "1234-5678-9977"},
+			BaseURL:    baseurl,
+			httpClient: http.DefaultClient,
+			debug:      true,
+		},
+		T: t,
+	}
+	a := &Dashboards{client: client}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := a.FindContext(ctx, nil); err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if client.InvokedCount != 0 {
+		t.Errorf("paginated search with cancelled context, expected 0 requests, got %d", client.InvokedCount)
+	}
+
+	// cancelling after the first page stops the search before the second
+	ctx, cancel = context.WithCancel(context.Background())
+	client.afterPage = cancel
+	if _, err := a.FindContext(ctx, nil); err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if client.InvokedCount != 1 {
+		t.Errorf("paginated search cancelled after one page, expected 1 request, got %d", client.InvokedCount)
+	}
+}
+
 func (m *MockCrudDashboardClient) Do(req *http.Request) (io.ReadCloser, error) {
 	response, err := ioutil.ReadFile("./fixtures/create-dashboard-response.json")
 	if err != nil {
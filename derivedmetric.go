@@ -0,0 +1,299 @@
+package wavefront
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+)
+
+// DerivedMetric represents a single Wavefront Derived Metric, a query that is
+// run on a schedule and whose results are stored as new metrics
+type DerivedMetric struct {
+	// Name is the name given to a Derived Metric
+	Name string `json:"name"`
+
+	// ID is the Wavefront-assigned ID of an existing Derived Metric
+	ID *string `json:"id,omitempty"`
+
+	// Query is the ts query whose results are stored
+	Query string `json:"query"`
+
+	// Minutes is how many minutes of data the Query is run over
+	Minutes int `json:"minutes"`
+
+	// ProcessRateMinutes is how often, in minutes, the Query is run
+	ProcessRateMinutes int `json:"processRateMinutes,omitempty"`
+
+	// AdditionalInfo is any extra information about the Derived Metric
+	AdditionalInfo string `json:"additionalInformation,omitempty"`
+
+	// IncludeObsoleteMetrics, if true, includes metrics that have not
+	// reported for a long time in the Query
+	IncludeObsoleteMetrics bool `json:"includeObsoleteMetrics,omitempty"`
+
+	// Tags are the tags applied to the Derived Metric
+	Tags []string
+
+	// Status is the current status of the Derived Metric
+	Status []string `json:"status,omitempty"`
+
+	// Deleted is true if the Derived Metric is in the trash
+	Deleted bool `json:"deleted,omitempty"`
+
+	CreatorId          string `json:"creatorId,omitempty"`
+	UpdaterId          string `json:"updaterId,omitempty"`
+	CreatedEpochMillis int64  `json:"createdEpochMillis,omitempty"`
+	UpdatedEpochMillis int64  `json:"updatedEpochMillis,omitempty"`
+
+	// unknown holds properties returned by Wavefront that are not modelled
+	// above, so that they survive a Get followed by an Update
+	unknown map[string]json.RawMessage
+}
+
+// DerivedMetrics is used to perform derived metric-related operations against
+// the Wavefront API
+type DerivedMetrics struct {
+	// client is the Wavefront client used to perform derived metric-related operations
+	client Wavefronter
+}
+
+const baseDerivedMetricPath = "/api/v2/derivedmetric"
+
+// derivedMetricFields are the JSON properties modelled by DerivedMetric
+var derivedMetricFields = jsonFieldNames(reflect.TypeOf(DerivedMetric{}), "tags")
+
+// UnmarshalJSON is a custom JSON unmarshaller for a DerivedMetric, used in
+// order to populate the Tags field in a more intuitive fashion
+func (d *DerivedMetric) UnmarshalJSON(b []byte) error {
+	type derivedMetric DerivedMetric
+	temp := struct {
+		Tags map[string][]string `json:"tags"`
+		*derivedMetric
+	}{
+		derivedMetric: (*derivedMetric)(d),
+	}
+	if err := json.Unmarshal(b, &temp); err != nil {
+		return err
+	}
+	d.Tags = temp.Tags["customerTags"]
+
+	var err error
+	d.unknown, err = unknownFields(b, derivedMetricFields)
+	return err
+}
+
+func (d *DerivedMetric) MarshalJSON() ([]byte, error) {
+	type derivedMetric DerivedMetric
+	b, err := json.Marshal(&struct {
+		Tags map[string][]string `json:"tags"`
+		*derivedMetric
+	}{
+		Tags: map[string][]string{
+			"customerTags": d.Tags,
+		},
+		derivedMetric: (*derivedMetric)(d),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return withUnknownFields(b, d.unknown)
+}
+
+// DerivedMetrics is used to return a client for derived metric-related operations
+func (c *Client) DerivedMetrics() *DerivedMetrics {
+	return &DerivedMetrics{client: c}
+}
+
+// Get is used to retrieve an existing DerivedMetric by ID.
+// The ID field must be provided
+func (d DerivedMetrics) Get(metric *DerivedMetric) error {
+	return d.GetContext(context.Background(), metric)
+}
+
+// GetContext is like Get but carries the given context through the request.
+func (d DerivedMetrics) GetContext(ctx context.Context, metric *DerivedMetric) error {
+	if metric.ID == nil || *metric.ID == "" {
+		return fmt.Errorf("DerivedMetric id field is not set")
+	}
+
+	return d.crudDerivedMetric(ctx, "GET", fmt.Sprintf("%s/%s", baseDerivedMetricPath, *metric.ID), metric)
+}
+
+// Find returns all derived metrics filtered by the given search conditions.
+// If filter is nil, all derived metrics are returned.
+func (d DerivedMetrics) Find(filter []*SearchCondition) ([]*DerivedMetric, error) {
+	return d.FindContext(context.Background(), filter)
+}
+
+// FindContext is like Find but carries the given context through every page
+// of the search. Pagination stops as soon as the context is done.
+func (d DerivedMetrics) FindContext(ctx context.Context, filter []*SearchCondition) ([]*DerivedMetric, error) {
+	search := &Search{
+		client: d.client,
+		Type:   "derivedmetric",
+		Params: &SearchParams{
+			Conditions: filter,
+		},
+	}
+
+	var results []*DerivedMetric
+	moreItems := true
+	for moreItems == true {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		resp, err := search.ExecuteContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		var tmpres []*DerivedMetric
+		err = json.Unmarshal(resp.Response.Items, &tmpres)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, tmpres...)
+		moreItems = resp.Response.MoreItems
+		search.Params.Offset = resp.NextOffset
+	}
+
+	return results, nil
+}
+
+// Create is used to create a DerivedMetric in Wavefront.
+// If successful, the ID field of the derived metric will be populated.
+func (d DerivedMetrics) Create(metric *DerivedMetric) error {
+	return d.CreateContext(context.Background(), metric)
+}
+
+// CreateContext is like Create but carries the given context through the request.
+func (d DerivedMetrics) CreateContext(ctx context.Context, metric *DerivedMetric) error {
+	return d.crudDerivedMetric(ctx, "POST", baseDerivedMetricPath, metric)
+}
+
+// Update is used to update an existing DerivedMetric.
+// The ID field of the derived metric must be populated
+func (d DerivedMetrics) Update(metric *DerivedMetric) error {
+	return d.UpdateContext(context.Background(), metric)
+}
+
+// UpdateContext is like Update but carries the given context through the request.
+func (d DerivedMetrics) UpdateContext(ctx context.Context, metric *DerivedMetric) error {
+	if metric.ID == nil {
+		return fmt.Errorf("derived metric id field not set")
+	}
+
+	return d.crudDerivedMetric(ctx, "PUT", fmt.Sprintf("%s/%s", baseDerivedMetricPath, *metric.ID), metric)
+}
+
+// Delete is used to delete an existing DerivedMetric. A live derived metric is
+// moved to the trash, and one already in the trash is deleted permanently.
+// The ID field of the derived metric must be populated
+func (d DerivedMetrics) Delete(metric *DerivedMetric) error {
+	return d.DeleteContext(context.Background(), metric)
+}
+
+// DeleteContext is like Delete but carries the given context through the request.
+func (d DerivedMetrics) DeleteContext(ctx context.Context, metric *DerivedMetric) error {
+	if metric.ID == nil {
+		return fmt.Errorf("derived metric id field not set")
+	}
+
+	err := d.crudDerivedMetric(ctx, "DELETE", fmt.Sprintf("%s/%s", baseDerivedMetricPath, *metric.ID), metric)
+	if err != nil {
+		return err
+	}
+
+	//reset the ID field so deletion is not attempted again
+	metric.ID = nil
+	return nil
+}
+
+// AddTag adds a tag to an existing DerivedMetric without updating the rest of it,
+// and adds it to the Tags of metric too.
+// The ID field of the derived metric must be populated
+func (d DerivedMetrics) AddTag(metric *DerivedMetric, tag string) error {
+	return d.AddTagContext(context.Background(), metric, tag)
+}
+
+// AddTagContext is like AddTag but carries the given context through the request.
+func (d DerivedMetrics) AddTagContext(ctx context.Context, metric *DerivedMetric, tag string) error {
+	if metric.ID == nil {
+		return fmt.Errorf("derived metric id field not set")
+	}
+
+	if err := addTag(ctx, d.client, fmt.Sprintf("%s/%s", baseDerivedMetricPath, *metric.ID), tag); err != nil {
+		return err
+	}
+	metric.Tags = withTag(metric.Tags, tag)
+	return nil
+}
+
+// RemoveTag removes a tag from an existing DerivedMetric without updating the rest of
+// it, and removes it from the Tags of metric too.
+// The ID field of the derived metric must be populated
+func (d DerivedMetrics) RemoveTag(metric *DerivedMetric, tag string) error {
+	return d.RemoveTagContext(context.Background(), metric, tag)
+}
+
+// RemoveTagContext is like RemoveTag but carries the given context through the request.
+func (d DerivedMetrics) RemoveTagContext(ctx context.Context, metric *DerivedMetric, tag string) error {
+	if metric.ID == nil {
+		return fmt.Errorf("derived metric id field not set")
+	}
+
+	if err := removeTag(ctx, d.client, fmt.Sprintf("%s/%s", baseDerivedMetricPath, *metric.ID), tag); err != nil {
+		return err
+	}
+	metric.Tags = withoutTag(metric.Tags, tag)
+	return nil
+}
+
+// SetTags replaces the tags of an existing DerivedMetric without updating the rest of
+// it, and sets the Tags of metric too. No tags removes every tag.
+// The ID field of the derived metric must be populated
+func (d DerivedMetrics) SetTags(metric *DerivedMetric, tags ...string) error {
+	return d.SetTagsContext(context.Background(), metric, tags...)
+}
+
+// SetTagsContext is like SetTags but carries the given context through the request.
+func (d DerivedMetrics) SetTagsContext(ctx context.Context, metric *DerivedMetric, tags ...string) error {
+	if metric.ID == nil {
+		return fmt.Errorf("derived metric id field not set")
+	}
+
+	if err := setTags(ctx, d.client, fmt.Sprintf("%s/%s", baseDerivedMetricPath, *metric.ID), tags); err != nil {
+		return err
+	}
+	metric.Tags = tags
+	return nil
+}
+
+func (d DerivedMetrics) crudDerivedMetric(ctx context.Context, method, path string, metric *DerivedMetric) error {
+	payload, err := json.Marshal(metric)
+	if err != nil {
+		return err
+	}
+	req, err := newRequestWithContext(ctx, d.client, method, path, nil, payload)
+	if err != nil {
+		return err
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Close()
+
+	body, err := ioutil.ReadAll(resp)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(body, &struct {
+		Response *DerivedMetric `json:"response"`
+	}{
+		Response: metric,
+	})
+}
@@ -0,0 +1,148 @@
+package wavefront
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+type MockDerivedMetricClient struct {
+	Client
+	InvokedCount int
+	T            *testing.T
+}
+
+type MockCrudDerivedMetricClient struct {
+	Client
+	method string
+	T      *testing.T
+}
+
+func (m *MockDerivedMetricClient) Do(req *http.Request) (io.ReadCloser, error) {
+	response, err := ioutil.ReadFile(fmt.Sprintf("./fixtures/paginated-derivedmetric-%d.json", m.InvokedCount))
+	if err != nil {
+		m.T.Fatal(err)
+	}
+	if req.URL.Path != "/api/v2/search/derivedmetric" {
+		m.T.Errorf("search path expected /api/v2/search/derivedmetric, got %s", req.URL.Path)
+	}
+	body, _ := ioutil.ReadAll(req.Body)
+	search := SearchParams{}
+	err = json.Unmarshal(body, &search)
+	if err != nil {
+		m.T.Fatal(err)
+	}
+	if search.Offset != search.Limit*m.InvokedCount {
+		m.T.Errorf("offset, expected %d, got %d", search.Limit*m.InvokedCount, search.Offset)
+	}
+	m.InvokedCount++
+	return ioutil.NopCloser(bytes.NewReader(response)), nil
+}
+
+func TestDerivedMetrics_PaginatedFind(t *testing.T) {
+	baseurl, _ := url.Parse("http://testing.wavefront.com")
+	d := &DerivedMetrics{
+		client: &MockDerivedMetricClient{
+			Client: Client{
+				Config:     &Config{Token: "1234-5678-9977"},
+				BaseURL:    baseurl,
+				httpClient: http.DefaultClient,
+				debug:      true,
+			},
+			T: t,
+		},
+	}
+	metrics, err := d.Find(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	invoked := ((d.client).(*MockDerivedMetricClient)).InvokedCount
+	if invoked != 2 {
+		t.Errorf("paginated search, expected 2, got %d", invoked)
+	}
+
+	if len(metrics) != 2 || metrics[1].Name != "Derived Metric 1" {
+		t.Errorf("unexpected derived metrics: %+v", metrics)
+	}
+
+	if len(metrics[1].Tags) != 2 {
+		t.Errorf("derived metric tags, expected 2, got %d", len(metrics[1].Tags))
+	}
+}
+
+func (m *MockCrudDerivedMetricClient) Do(req *http.Request) (io.ReadCloser, error) {
+	response, err := ioutil.ReadFile("./fixtures/create-derivedmetric-response.json")
+	if err != nil {
+		m.T.Fatal(err)
+	}
+	if req.Method != m.method {
+		m.T.Errorf("request method expected '%s' got '%s'", m.method, req.Method)
+	}
+	body, _ := ioutil.ReadAll(req.Body)
+	metric := DerivedMetric{}
+	err = json.Unmarshal(body, &metric)
+	if err != nil {
+		m.T.Fatal(err)
+	}
+	return ioutil.NopCloser(bytes.NewReader(response)), nil
+}
+
+func TestDerivedMetrics_CreateUpdateDeleteDerivedMetric(t *testing.T) {
+	baseurl, _ := url.Parse("http://testing.wavefront.com")
+	d := &DerivedMetrics{
+		client: &MockCrudDerivedMetricClient{
+			Client: Client{
+				Config:     &Config{Token: "1234-5678-9977"},
+				BaseURL:    baseurl,
+				httpClient: http.DefaultClient,
+				debug:      true,
+			},
+			method: "PUT",
+			T:      t,
+		},
+	}
+
+	metric := DerivedMetric{
+		Name:               "test derived metric",
+		Query:              `aliasMetric(sum(rate(ts(requests.count))), "requests.rate")`,
+		Minutes:            5,
+		ProcessRateMinutes: 1,
+		AdditionalInfo:     "request rate across all services",
+		Tags:               []string{"mytag1", "mytag2"},
+	}
+
+	if err := d.Update(&metric); err == nil {
+		t.Errorf("expected derived metric update to error with no ID")
+	}
+
+	d.client.(*MockCrudDerivedMetricClient).method = "POST"
+
+	if err := d.Create(&metric); err != nil {
+		t.Fatal(err)
+	}
+	if *metric.ID != "1234" {
+		t.Errorf("derived metric ID expected 1234, got %s", *metric.ID)
+	}
+	if len(metric.Status) != 1 || metric.Status[0] != "ACTIVE" {
+		t.Errorf("status expected [ACTIVE], got %v", metric.Status)
+	}
+
+	d.client.(*MockCrudDerivedMetricClient).method = "PUT"
+	if err := d.Update(&metric); err != nil {
+		t.Error(err)
+	}
+
+	d.client.(*MockCrudDerivedMetricClient).method = "DELETE"
+	if err := d.Delete(&metric); err != nil {
+		t.Error(err)
+	}
+
+	if metric.ID != nil {
+		t.Error("expected derived metric ID to be reset after deletion")
+	}
+}
@@ -0,0 +1,110 @@
+package wavefront
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// APIError is returned by Client.Do, and so by every entity operation, when
+// the Wavefront API responds with a non-200 status.
+type APIError struct {
+	// StatusCode is the HTTP status code of the response (e.g. 404)
+	StatusCode int
+
+	// Status is the HTTP status line of the response (e.g. "404 Not Found")
+	Status string
+
+	// Method is the HTTP method of the failed request
+	Method string
+
+	// Path is the URL path of the failed request
+	Path string
+
+	// Message is the error message reported by Wavefront, if any
+	Message string
+
+	// Code is the error code reported by Wavefront, if any
+	Code int
+
+	// Body is the raw response body
+	Body []byte
+}
+
+// newAPIError builds an APIError from a failed response and its body.
+func newAPIError(resp *http.Response, body []byte) *APIError {
+	e := &APIError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Body:       body,
+	}
+	if resp.Request != nil {
+		e.Method = resp.Request.Method
+		e.Path = resp.Request.URL.Path
+	}
+
+	// Wavefront usually wraps errors in a status object, but some
+	// responses (e.g. from the auth layer) carry them at the top level
+	var parsed struct {
+		Status struct {
+			Message string `json:"message"`
+			Code    int    `json:"code"`
+		} `json:"status"`
+		Message string `json:"message"`
+		Code    int    `json:"code"`
+	}
+	if err := json.Unmarshal(body, &parsed); err == nil {
+		e.Message, e.Code = parsed.Status.Message, parsed.Status.Code
+		if e.Message == "" {
+			e.Message, e.Code = parsed.Message, parsed.Code
+		}
+	}
+	return e
+}
+
+func (e *APIError) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = string(e.Body)
+	}
+	if msg == "" {
+		return fmt.Sprintf("%s %s: server returned %s", e.Method, e.Path, e.Status)
+	}
+	return fmt.Sprintf("%s %s: server returned %s: %s", e.Method, e.Path, e.Status, msg)
+}
+
+// ErrNotFound is returned, wrapped with what was looked for, when a lookup
+// which Wavefront answers successfully finds nothing, e.g. Events.FindByID.
+var ErrNotFound = errors.New("not found")
+
+// IsNotFound reports whether err is an APIError with a 404 status, or is
+// ErrNotFound
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound) || errors.Is(err, ErrNotFound)
+}
+
+// IsUnauthorized reports whether err is an APIError with a 401 status
+func IsUnauthorized(err error) bool {
+	return hasStatus(err, http.StatusUnauthorized)
+}
+
+// IsForbidden reports whether err is an APIError with a 403 status
+func IsForbidden(err error) bool {
+	return hasStatus(err, http.StatusForbidden)
+}
+
+// IsConflict reports whether err is an APIError with a 409 status
+func IsConflict(err error) bool {
+	return hasStatus(err, http.StatusConflict)
+}
+
+// IsRateLimited reports whether err is an APIError with a 429 status
+func IsRateLimited(err error) bool {
+	return hasStatus(err, http.StatusTooManyRequests)
+}
+
+func hasStatus(err error, code int) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == code
+}
@@ -0,0 +1,75 @@
+package wavefront
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAPIError(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"status":{"result":"ERROR","message":"Alert 1234 not found","code":404}}`))
+	}))
+	defer srv.Close()
+
+	client, err := NewClient(&Config{
+		Address:       strings.TrimLeft(srv.URL, "https://"),
+		Token:         "123456789",
+		SkipTLSVerify: true,
+	})
+	if err != nil {
+		t.Fatal("error initiating client:", err)
+	}
+
+	id := "1234"
+	err = client.Alerts().Get(&Alert{ID: &id})
+	if err == nil {
+		t.Fatal("expected error getting missing alert")
+	}
+
+	apiErr, ok := err.(*APIError)
+	if !ok {
+		t.Fatalf("expected *APIError, got %T", err)
+	}
+	if apiErr.StatusCode != 404 || apiErr.Code != 404 {
+		t.Errorf("status code, expected 404, got %d (code %d)", apiErr.StatusCode, apiErr.Code)
+	}
+	if apiErr.Method != "GET" || apiErr.Path != "/api/v2/alert/1234" {
+		t.Errorf("request, expected GET /api/v2/alert/1234, got %s %s", apiErr.Method, apiErr.Path)
+	}
+	if apiErr.Message != "Alert 1234 not found" {
+		t.Errorf("message, expected 'Alert 1234 not found', got %s", apiErr.Message)
+	}
+
+	if !IsNotFound(err) {
+		t.Error("expected IsNotFound to be true")
+	}
+	if !IsNotFound(fmt.Errorf("wrapped: %w", err)) {
+		t.Error("expected IsNotFound to be true for a wrapped error")
+	}
+	if IsRateLimited(err) || IsConflict(err) {
+		t.Error("expected other status helpers to be false")
+	}
+}
+
+func TestAPIError_TopLevelMessage(t *testing.T) {
+	resp := &http.Response{
+		StatusCode: http.StatusUnauthorized,
+		Status:     "401 Unauthorized",
+	}
+	err := newAPIError(resp, []byte(`{"code":401,"message":"invalid token"}`))
+	if err.Message != "invalid token" || err.Code != 401 {
+		t.Errorf("expected top-level message and code, got %q (%d)", err.Message, err.Code)
+	}
+	if !IsUnauthorized(err) {
+		t.Error("expected IsUnauthorized to be true")
+	}
+
+	err = newAPIError(resp, []byte("not json"))
+	if !strings.Contains(err.Error(), "not json") {
+		t.Errorf("expected raw body in error string, got %s", err.Error())
+	}
+}
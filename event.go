@@ -1,9 +1,11 @@
 package wavefront
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"reflect"
 	"time"
 )
 
@@ -37,6 +39,10 @@ type Event struct {
 
 	// Instantaneous, if true, creates a point-in-time Event (i.e. with no duration)
 	Instantaneous bool `json:"isEphemeral"`
+
+	// unknown holds properties returned by Wavefront that are not modelled
+	// above, so that they survive a Get followed by an Update
+	unknown map[string]json.RawMessage
 }
 
 // Events is used to perform event-related operations against the Wavefront API
@@ -47,6 +53,9 @@ type Events struct {
 
 const baseEventPath = "/api/v2/event"
 
+// eventFields are the JSON properties modelled by Event
+var eventFields = jsonFieldNames(reflect.TypeOf(Event{}), "annotations")
+
 // UnmarshalJSON is a custom JSON unmarshaller for an Event, used to explode
 // the annotations.
 func (e *Event) UnmarshalJSON(b []byte) error {
@@ -64,12 +73,14 @@ func (e *Event) UnmarshalJSON(b []byte) error {
 	e.Type = temp.Annotations["type"]
 	e.Details = temp.Annotations["details"]
 
-	return nil
+	var err error
+	e.unknown, err = unknownFields(b, eventFields)
+	return err
 }
 
 func (e *Event) MarshalJSON() ([]byte, error) {
 	type event Event
-	return json.Marshal(&struct {
+	b, err := json.Marshal(&struct {
 		Annotations map[string]string `json:"annotations"`
 		*event
 	}{
@@ -80,6 +91,10 @@ func (e *Event) MarshalJSON() ([]byte, error) {
 		},
 		event: (*event)(e),
 	})
+	if err != nil {
+		return nil, err
+	}
+	return withUnknownFields(b, e.unknown)
 }
 
 // Events is used to return a client for event-related operations
@@ -92,6 +107,11 @@ func (c *Client) Events() *Events {
 // the first 100 entries. If more results are required the Search type can
 // be used directly.
 func (e Events) Find(filter []*SearchCondition, timeRange *TimeRange) ([]*Event, error) {
+	return e.FindContext(context.Background(), filter, timeRange)
+}
+
+// FindContext is like Find but carries the given context through the request.
+func (e Events) FindContext(ctx context.Context, filter []*SearchCondition, timeRange *TimeRange) ([]*Event, error) {
 	search := &Search{
 		client: e.client,
 		Type:   "event",
@@ -101,7 +121,7 @@ func (e Events) Find(filter []*SearchCondition, timeRange *TimeRange) ([]*Event,
 		},
 	}
 	var results []*Event
-	resp, err := search.Execute()
+	resp, err := search.ExecuteContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -115,9 +135,14 @@ func (e Events) Find(filter []*SearchCondition, timeRange *TimeRange) ([]*Event,
 }
 
 // FindByID returns the Event with the Wavefront-assigned ID.
-// If not found an error is returned
+// If not found an error is returned, for which IsNotFound is true
 func (e Events) FindByID(id string) (*Event, error) {
-	res, err := e.Find([]*SearchCondition{
+	return e.FindByIDContext(context.Background(), id)
+}
+
+// FindByIDContext is like FindByID but carries the given context through the request.
+func (e Events) FindByIDContext(ctx context.Context, id string) (*Event, error) {
+	res, err := e.FindContext(ctx, []*SearchCondition{
 		&SearchCondition{
 			Key:            "id",
 			Value:          id,
@@ -130,7 +155,7 @@ func (e Events) FindByID(id string) (*Event, error) {
 	}
 
 	if len(res) == 0 {
-		return nil, fmt.Errorf("no event found with ID %s", id)
+		return nil, fmt.Errorf("event %s: %w", id, ErrNotFound)
 	}
 
 	return res[0], nil
@@ -139,43 +164,63 @@ func (e Events) FindByID(id string) (*Event, error) {
 // Create is used to create an Event in Wavefront.
 // If successful, the ID field of the event will be populated.
 func (a Events) Create(event *Event) error {
+	return a.CreateContext(context.Background(), event)
+}
+
+// CreateContext is like Create but carries the given context through the request.
+func (a Events) CreateContext(ctx context.Context, event *Event) error {
 	if event.StartTime == 0 {
 		event.StartTime = time.Now().Unix() * 1000
 	}
 	if event.Instantaneous == true {
 		event.EndTime = event.StartTime + 1
 	}
-	return a.crudEvent("POST", baseEventPath, event)
+	return a.crudEvent(ctx, "POST", baseEventPath, event)
 }
 
 // Update is used to update an existing Event.
 // The ID field of the Event must be populated
 func (e Events) Update(event *Event) error {
+	return e.UpdateContext(context.Background(), event)
+}
+
+// UpdateContext is like Update but carries the given context through the request.
+func (e Events) UpdateContext(ctx context.Context, event *Event) error {
 	if event.ID == nil {
 		return fmt.Errorf("Event id field not set")
 	}
 
-	return e.crudEvent("PUT", fmt.Sprintf("%s/%s", baseEventPath, *event.ID), event)
+	return e.crudEvent(ctx, "PUT", fmt.Sprintf("%s/%s", baseEventPath, *event.ID), event)
 
 }
 
 // Close is used to close an existing Event
 func (e Events) Close(event *Event) error {
+	return e.CloseContext(context.Background(), event)
+}
+
+// CloseContext is like Close but carries the given context through the request.
+func (e Events) CloseContext(ctx context.Context, event *Event) error {
 	if event.ID == nil {
 		return fmt.Errorf("Event id field not set")
 	}
 
-	return e.crudEvent("POST", fmt.Sprintf("%s/%s/close", baseEventPath, *event.ID), event)
+	return e.crudEvent(ctx, "POST", fmt.Sprintf("%s/%s/close", baseEventPath, *event.ID), event)
 }
 
 // Delete is used to delete an existing Event.
 // The ID field of the Event must be populated
 func (e Events) Delete(event *Event) error {
+	return e.DeleteContext(context.Background(), event)
+}
+
+// DeleteContext is like Delete but carries the given context through the request.
+func (e Events) DeleteContext(ctx context.Context, event *Event) error {
 	if event.ID == nil {
 		return fmt.Errorf("Event id field not set")
 	}
 
-	err := e.crudEvent("DELETE", fmt.Sprintf("%s/%s", baseEventPath, *event.ID), event)
+	err := e.crudEvent(ctx, "DELETE", fmt.Sprintf("%s/%s", baseEventPath, *event.ID), event)
 	if err != nil {
 		return err
 	}
@@ -186,12 +231,72 @@ func (e Events) Delete(event *Event) error {
 
 }
 
-func (e Events) crudEvent(method, path string, event *Event) error {
+// AddTag adds a tag to an existing Event without updating the rest of it,
+// and adds it to the Tags of event too.
+// The ID field of the Event must be populated
+func (e Events) AddTag(event *Event, tag string) error {
+	return e.AddTagContext(context.Background(), event, tag)
+}
+
+// AddTagContext is like AddTag but carries the given context through the request.
+func (e Events) AddTagContext(ctx context.Context, event *Event, tag string) error {
+	if event.ID == nil {
+		return fmt.Errorf("Event id field not set")
+	}
+
+	if err := addTag(ctx, e.client, fmt.Sprintf("%s/%s", baseEventPath, *event.ID), tag); err != nil {
+		return err
+	}
+	event.Tags = withTag(event.Tags, tag)
+	return nil
+}
+
+// RemoveTag removes a tag from an existing Event without updating the rest of
+// it, and removes it from the Tags of event too.
+// The ID field of the Event must be populated
+func (e Events) RemoveTag(event *Event, tag string) error {
+	return e.RemoveTagContext(context.Background(), event, tag)
+}
+
+// RemoveTagContext is like RemoveTag but carries the given context through the request.
+func (e Events) RemoveTagContext(ctx context.Context, event *Event, tag string) error {
+	if event.ID == nil {
+		return fmt.Errorf("Event id field not set")
+	}
+
+	if err := removeTag(ctx, e.client, fmt.Sprintf("%s/%s", baseEventPath, *event.ID), tag); err != nil {
+		return err
+	}
+	event.Tags = withoutTag(event.Tags, tag)
+	return nil
+}
+
+// SetTags replaces the tags of an existing Event without updating the rest of
+// it, and sets the Tags of event too. No tags removes every tag.
+// The ID field of the Event must be populated
+func (e Events) SetTags(event *Event, tags ...string) error {
+	return e.SetTagsContext(context.Background(), event, tags...)
+}
+
+// SetTagsContext is like SetTags but carries the given context through the request.
+func (e Events) SetTagsContext(ctx context.Context, event *Event, tags ...string) error {
+	if event.ID == nil {
+		return fmt.Errorf("Event id field not set")
+	}
+
+	if err := setTags(ctx, e.client, fmt.Sprintf("%s/%s", baseEventPath, *event.ID), tags); err != nil {
+		return err
+	}
+	event.Tags = tags
+	return nil
+}
+
+func (e Events) crudEvent(ctx context.Context, method, path string, event *Event) error {
 	payload, err := json.Marshal(event)
 	if err != nil {
 		return err
 	}
-	req, err := e.client.NewRequest(method, path, nil, payload)
+	req, err := newRequestWithContext(ctx, e.client, method, path, nil, payload)
 	if err != nil {
 		return err
 	}
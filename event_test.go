@@ -3,6 +3,7 @@ package wavefront
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"io"
 	"io/ioutil"
 	"net/http"
@@ -75,6 +76,38 @@ func TestEvents_Find(t *testing.T) {
 
 }
 
+type MockEmptyEventClient struct {
+	Client
+}
+
+func (m *MockEmptyEventClient) Do(req *http.Request) (io.ReadCloser, error) {
+	response := `{"response":{"items":[],"moreItems":false}}`
+	return ioutil.NopCloser(bytes.NewReader([]byte(response))), nil
+}
+
+func TestEvents_FindByIDNotFound(t *testing.T) {
+	baseurl, _ := url.Parse("http://testing.wavefront.com")
+	e := &Events{
+		client: &MockEmptyEventClient{
+			Client: Client{
+				Config:     &Config{Token: "1234-5678-9977"},
+				BaseURL:    baseurl,
+				httpClient: http.DefaultClient,
+			},
+		},
+	}
+
+	event, err := e.FindByID("1498664617084:missing")
+	if event != nil || !IsNotFound(err) || !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected a not found error, got %v and %v", event, err)
+	}
+	// the search itself succeeded, so no API error is reported
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		t.Errorf("expected no API error, got %v", apiErr)
+	}
+}
+
 func (m *MockCrudEventClient) Do(req *http.Request) (io.ReadCloser, error) {
 	response, err := ioutil.ReadFile("./fixtures/create-event-response.json")
 	if err != nil {
@@ -174,6 +174,18 @@ func main() {
 		log.Fatal(err)
 	}
 
+	// Snooze the Threshold Alert for an hour, then unsnooze it
+	err = alerts.Snooze(mta, 3600)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("threshold alert snoozed until", mta.Snoozed)
+
+	err = alerts.Unsnooze(mta)
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	// Update the Threshold Alert
 	mta.Targets["smoke"] = strC.String()
 	err = alerts.Update(mta)
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/spaceapegames/go-wavefront"
+)
+
+func main() {
+	config := &wavefront.Config{
+		Address: "test.wavefront.com",
+		Token:   "xxxx-xxxx-xxxx-xxxx-xxxx",
+	}
+	client, err := wavefront.NewClient(config)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	metrics := client.DerivedMetrics()
+
+	metric := &wavefront.DerivedMetric{
+		Name:               "Request rate",
+		Query:              `aliasMetric(sum(rate(ts(requests.count))), "requests.rate")`,
+		Minutes:            5,
+		ProcessRateMinutes: 1,
+		Tags:               []string{"requests"},
+	}
+
+	// Create the derived metric on Wavefront
+	err = metrics.Create(metric)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// The ID field is now set, so we can update/delete the derived metric
+	fmt.Println("derived metric ID is", *metric.ID)
+
+	// Find all derived metrics tagged 'requests'
+	found, err := metrics.Find([]*wavefront.SearchCondition{
+		&wavefront.SearchCondition{
+			Key:            "tags",
+			Value:          "requests",
+			MatchingMethod: "EXACT",
+		},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("found", len(found), "derived metrics")
+
+	// Run the query less often
+	metric.ProcessRateMinutes = 5
+	err = metrics.Update(metric)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Delete the derived metric
+	err = metrics.Delete(metric)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println("derived metric deleted")
+}
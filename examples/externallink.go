@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/spaceapegames/go-wavefront"
+)
+
+func main() {
+	config := &wavefront.Config{
+		Address: "test.wavefront.com",
+		Token:   "xxxx-xxxx-xxxx-xxxx-xxxx",
+	}
+	client, err := wavefront.NewClient(config)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	links := client.ExternalLinks()
+
+	// Link from production charts of web servers to their logs
+	link := &wavefront.ExternalLink{
+		Name:              "Logs",
+		Description:       "Logs for the source over the chart's time range",
+		Template:          "https://logs.example.com/search?host={{source}}&from={{startEpochMillis}}&to={{endEpochMillis}}",
+		SourceFilterRegex: "^web-.*",
+		PointTagFilterRegexes: map[string]string{
+			"env": "^prod$",
+		},
+	}
+
+	// Create the external link on Wavefront
+	err = links.Create(link)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// The ID field is now set, so we can update/delete the external link
+	fmt.Println("external link ID is", *link.ID)
+
+	// Apply the link to staging too
+	link.PointTagFilterRegexes["env"] = "^(prod|staging)$"
+	err = links.Update(link)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Delete the external link
+	err = links.Delete(link)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println("external link deleted")
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/spaceapegames/go-wavefront"
+)
+
+func main() {
+	config := &wavefront.Config{
+		Address: "test.wavefront.com",
+		Token:   "xxxx-xxxx-xxxx-xxxx-xxxx",
+	}
+	client, err := wavefront.NewClient(config)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	windows := client.MaintenanceWindows()
+
+	// Silence alerts tagged 'db' for the next two hours
+	start := time.Now()
+	window := &wavefront.MaintenanceWindow{
+		Title:                "Database release",
+		Reason:               "Deploying schema changes",
+		StartTimeInSeconds:   start.Unix(),
+		EndTimeInSeconds:     start.Add(2 * time.Hour).Unix(),
+		RelevantCustomerTags: []string{"db"},
+	}
+
+	// Create the maintenance window on Wavefront
+	err = windows.Create(window)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// The ID field is now set, so we can update/delete the maintenance window
+	fmt.Println("maintenance window ID is", *window.ID)
+
+	// End the maintenance window early
+	window.EndTimeInSeconds = time.Now().Add(time.Minute).Unix()
+	err = windows.Update(window)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Delete the maintenance window
+	err = windows.Delete(window)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println("maintenance window deleted")
+}
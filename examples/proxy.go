@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/spaceapegames/go-wavefront"
+)
+
+func main() {
+	config := &wavefront.Config{
+		Address: "test.wavefront.com",
+		Token:   "xxxx-xxxx-xxxx-xxxx-xxxx",
+	}
+	client, err := wavefront.NewClient(config)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	proxies := client.Proxies()
+
+	// List every proxy with its version and buffer usage
+	all, err := proxies.List()
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, proxy := range all {
+		fmt.Printf("%s (%s) version %s, %d tasks queued\n",
+			proxy.Name, proxy.Status, proxy.Version, proxy.LocalQueueSize)
+	}
+
+	// Find proxies which have not checked in for 10 minutes
+	stale, err := proxies.FindStale(10 * time.Minute)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, proxy := range stale {
+		fmt.Printf("%s on %s last checked in at %s\n",
+			proxy.Name, proxy.Hostname, proxy.LastCheckIn())
+	}
+}
@@ -19,7 +19,7 @@ func main() {
 		log.Fatal(err)
 	}
 
-	// enable debug - all requests get dumped to stdout before being executed
+	// enable debug - all requests and responses get logged to stdout, with credentials redacted
 	client.Debug(true)
 
 	// NewQueryParams generates a query using the given ts expression.
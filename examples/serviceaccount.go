@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+
+	"github.com/spaceapegames/go-wavefront"
+)
+
+func main() {
+	config := &wavefront.Config{
+		Address: "test.wavefront.com",
+		Token:   "xxxx-xxxx-xxxx-xxxx-xxxx",
+	}
+	client, err := wavefront.NewClient(config)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Create a service account for CI, which can post events
+	id := "sa::ci"
+	account := &wavefront.ServiceAccount{
+		ID:          &id,
+		Description: "Continuous integration",
+		Active:      true,
+		Permissions: []string{"events_management"},
+	}
+	err = client.ServiceAccounts().Create(account)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Give it a token
+	tokens := client.APITokens()
+	token, err := tokens.CreateForServiceAccount(id, "deploys")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Later, replace the token, storing the new one before the old one is revoked
+	token, err = tokens.Rotate(token, func(t *wavefront.APIToken) error {
+		return ioutil.WriteFile("ci-token", []byte(*t.ID), 0600)
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println("rotated token", token.Name)
+
+	// Delete the service account, and its tokens
+	err = client.ServiceAccounts().Delete(account)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
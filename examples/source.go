@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/spaceapegames/go-wavefront"
+)
+
+func main() {
+	config := &wavefront.Config{
+		Address: "test.wavefront.com",
+		Token:   "xxxx-xxxx-xxxx-xxxx-xxxx",
+	}
+	client, err := wavefront.NewClient(config)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	sources := client.Sources()
+
+	// Roles of hosts, e.g. from an inventory system
+	roles := map[string]string{
+		"web-1": "role.web",
+		"db-1":  "role.db",
+	}
+
+	for host, role := range roles {
+		source := &wavefront.MetricSource{ID: &host}
+		err = sources.Get(source)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		// Add the role tag, leaving any other source tags alone
+		err = sources.AddTag(source, role)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// Find every web server
+	web, err := sources.FindByTag("role.web")
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, source := range web {
+		fmt.Println(*source.ID, source.Tags)
+	}
+}
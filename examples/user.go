@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/spaceapegames/go-wavefront"
+)
+
+func main() {
+	config := &wavefront.Config{
+		Address: "test.wavefront.com",
+		Token:   "xxxx-xxxx-xxxx-xxxx-xxxx",
+	}
+	client, err := wavefront.NewClient(config)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Create a role which can manage alerts
+	role := &wavefront.Role{
+		Name:        "Operators",
+		Description: "Manage alerts and events",
+		Permissions: []string{"alerts_management"},
+	}
+	err = client.Roles().Create(role)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Let the role manage events too
+	err = client.Roles().GrantPermission("events_management", *role.ID)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Create a user group with the role
+	group := &wavefront.UserGroup{
+		Name:  "On-call",
+		Roles: []string{*role.ID},
+	}
+	err = client.UserGroups().Create(group)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Invite a user, who is sent an email, and add them to the group
+	email := "someone@example.com"
+	user := &wavefront.User{ID: &email}
+	err = client.Users().Invite(user)
+	if err != nil {
+		log.Fatal(err)
+	}
+	err = client.UserGroups().AddUsers(group, *user.ID)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Printf("user group %s has %d users\n", group.Name, group.UserCount)
+
+	// Tidy up
+	for _, err := range []error{
+		client.Users().Delete(user),
+		client.UserGroups().Delete(group),
+		client.Roles().Delete(role),
+	} {
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package wavefront
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+)
+
+// ExternalLink represents a single Wavefront External Link, a URL template
+// used to link from a chart to another tool such as a log viewer
+type ExternalLink struct {
+	// ID is the Wavefront-assigned ID of an existing External Link
+	ID *string `json:"id,omitempty"`
+
+	// Name is the name given to the External Link
+	Name string `json:"name"`
+
+	// Description is a description of the External Link
+	Description string `json:"description"`
+
+	// Template is the mustache template of the link's URL, which can refer
+	// to the chart's metric, source and point tags and time range
+	Template string `json:"template"`
+
+	// MetricFilterRegex, if set, restricts the link to metrics matching it
+	MetricFilterRegex string `json:"metricFilterRegex,omitempty"`
+
+	// SourceFilterRegex, if set, restricts the link to sources matching it
+	SourceFilterRegex string `json:"sourceFilterRegex,omitempty"`
+
+	// PointTagFilterRegexes, if set, restricts the link to time series whose
+	// point tags match the regular expression given for each tag key
+	PointTagFilterRegexes map[string]string `json:"pointTagFilterRegexes,omitempty"`
+
+	// IsLogIntegration is true if the link is used to open logs
+	IsLogIntegration bool `json:"isLogIntegration,omitempty"`
+
+	CreatorId          string `json:"creatorId,omitempty"`
+	UpdaterId          string `json:"updaterId,omitempty"`
+	CreatedEpochMillis int64  `json:"createdEpochMillis,omitempty"`
+	UpdatedEpochMillis int64  `json:"updatedEpochMillis,omitempty"`
+
+	// unknown holds properties returned by Wavefront that are not modelled
+	// above, so that they survive a Get followed by an Update
+	unknown map[string]json.RawMessage
+}
+
+// ExternalLinks is used to perform external link-related operations
+// against the Wavefront API
+type ExternalLinks struct {
+	// client is the Wavefront client used to perform external link-related operations
+	client Wavefronter
+}
+
+const baseExternalLinkPath = "/api/v2/extlink"
+
+// externalLinkFields are the JSON properties modelled by ExternalLink
+var externalLinkFields = jsonFieldNames(reflect.TypeOf(ExternalLink{}))
+
+// UnmarshalJSON is a custom JSON unmarshaller for an ExternalLink, used in
+// order to keep properties that are not modelled by ExternalLink
+func (e *ExternalLink) UnmarshalJSON(b []byte) error {
+	type externalLink ExternalLink
+	if err := json.Unmarshal(b, (*externalLink)(e)); err != nil {
+		return err
+	}
+
+	var err error
+	e.unknown, err = unknownFields(b, externalLinkFields)
+	return err
+}
+
+func (e *ExternalLink) MarshalJSON() ([]byte, error) {
+	type externalLink ExternalLink
+	b, err := json.Marshal((*externalLink)(e))
+	if err != nil {
+		return nil, err
+	}
+	return withUnknownFields(b, e.unknown)
+}
+
+// ExternalLinks is used to return a client for external link-related operations
+func (c *Client) ExternalLinks() *ExternalLinks {
+	return &ExternalLinks{client: c}
+}
+
+// Get is used to retrieve an existing ExternalLink by ID.
+// The ID field must be provided
+func (e ExternalLinks) Get(link *ExternalLink) error {
+	return e.GetContext(context.Background(), link)
+}
+
+// GetContext is like Get but carries the given context through the request.
+func (e ExternalLinks) GetContext(ctx context.Context, link *ExternalLink) error {
+	if link.ID == nil || *link.ID == "" {
+		return fmt.Errorf("ExternalLink id field is not set")
+	}
+
+	return e.crudExternalLink(ctx, "GET", fmt.Sprintf("%s/%s", baseExternalLinkPath, *link.ID), link)
+}
+
+// Find returns all external links filtered by the given search conditions.
+// If filter is nil, all external links are returned.
+func (e ExternalLinks) Find(filter []*SearchCondition) ([]*ExternalLink, error) {
+	return e.FindContext(context.Background(), filter)
+}
+
+// FindContext is like Find but carries the given context through every page
+// of the search. Pagination stops as soon as the context is done.
+func (e ExternalLinks) FindContext(ctx context.Context, filter []*SearchCondition) ([]*ExternalLink, error) {
+	search := &Search{
+		client: e.client,
+		Type:   "extlink",
+		Params: &SearchParams{
+			Conditions: filter,
+		},
+	}
+
+	var results []*ExternalLink
+	moreItems := true
+	for moreItems == true {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		resp, err := search.ExecuteContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		var tmpres []*ExternalLink
+		err = json.Unmarshal(resp.Response.Items, &tmpres)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, tmpres...)
+		moreItems = resp.Response.MoreItems
+		search.Params.Offset = resp.NextOffset
+	}
+
+	return results, nil
+}
+
+// Create is used to create a ExternalLink in Wavefront.
+// If successful, the ID field of the external link will be populated.
+func (e ExternalLinks) Create(link *ExternalLink) error {
+	return e.CreateContext(context.Background(), link)
+}
+
+// CreateContext is like Create but carries the given context through the request.
+func (e ExternalLinks) CreateContext(ctx context.Context, link *ExternalLink) error {
+	return e.crudExternalLink(ctx, "POST", baseExternalLinkPath, link)
+}
+
+// Update is used to update an existing ExternalLink.
+// The ID field of the external link must be populated
+func (e ExternalLinks) Update(link *ExternalLink) error {
+	return e.UpdateContext(context.Background(), link)
+}
+
+// UpdateContext is like Update but carries the given context through the request.
+func (e ExternalLinks) UpdateContext(ctx context.Context, link *ExternalLink) error {
+	if link.ID == nil {
+		return fmt.Errorf("external link id field not set")
+	}
+
+	return e.crudExternalLink(ctx, "PUT", fmt.Sprintf("%s/%s", baseExternalLinkPath, *link.ID), link)
+}
+
+// Delete is used to delete an existing ExternalLink.
+// The ID field of the external link must be populated
+func (e ExternalLinks) Delete(link *ExternalLink) error {
+	return e.DeleteContext(context.Background(), link)
+}
+
+// DeleteContext is like Delete but carries the given context through the request.
+func (e ExternalLinks) DeleteContext(ctx context.Context, link *ExternalLink) error {
+	if link.ID == nil {
+		return fmt.Errorf("external link id field not set")
+	}
+
+	err := e.crudExternalLink(ctx, "DELETE", fmt.Sprintf("%s/%s", baseExternalLinkPath, *link.ID), link)
+	if err != nil {
+		return err
+	}
+
+	//reset the ID field so deletion is not attempted again
+	link.ID = nil
+	return nil
+}
+
+func (e ExternalLinks) crudExternalLink(ctx context.Context, method, path string, link *ExternalLink) error {
+	payload, err := json.Marshal(link)
+	if err != nil {
+		return err
+	}
+	req, err := newRequestWithContext(ctx, e.client, method, path, nil, payload)
+	if err != nil {
+		return err
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Close()
+
+	body, err := ioutil.ReadAll(resp)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(body, &struct {
+		Response *ExternalLink `json:"response"`
+	}{
+		Response: link,
+	})
+}
@@ -0,0 +1,146 @@
+package wavefront
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+type MockExternalLinkClient struct {
+	Client
+	InvokedCount int
+	T            *testing.T
+}
+
+type MockCrudExternalLinkClient struct {
+	Client
+	method string
+	T      *testing.T
+}
+
+func (m *MockExternalLinkClient) Do(req *http.Request) (io.ReadCloser, error) {
+	response, err := ioutil.ReadFile(fmt.Sprintf("./fixtures/paginated-extlink-%d.json", m.InvokedCount))
+	if err != nil {
+		m.T.Fatal(err)
+	}
+	if req.URL.Path != "/api/v2/search/extlink" {
+		m.T.Errorf("search path expected /api/v2/search/extlink, got %s", req.URL.Path)
+	}
+	body, _ := ioutil.ReadAll(req.Body)
+	search := SearchParams{}
+	err = json.Unmarshal(body, &search)
+	if err != nil {
+		m.T.Fatal(err)
+	}
+	if search.Offset != search.Limit*m.InvokedCount {
+		m.T.Errorf("offset, expected %d, got %d", search.Limit*m.InvokedCount, search.Offset)
+	}
+	m.InvokedCount++
+	return ioutil.NopCloser(bytes.NewReader(response)), nil
+}
+
+func TestExternalLinks_PaginatedFind(t *testing.T) {
+	baseurl, _ := url.Parse("http://testing.wavefront.com")
+	e := &ExternalLinks{
+		client: &MockExternalLinkClient{
+			Client: Client{
+				Config:     &Config{Token: "1234-5678-9977"},
+				BaseURL:    baseurl,
+				httpClient: http.DefaultClient,
+				debug:      true,
+			},
+			T: t,
+		},
+	}
+	links, err := e.Find(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	invoked := ((e.client).(*MockExternalLinkClient)).InvokedCount
+	if invoked != 2 {
+		t.Errorf("paginated search, expected 2, got %d", invoked)
+	}
+
+	if len(links) != 2 || links[1].Name != "Link 1" {
+		t.Errorf("unexpected external links: %+v", links)
+	}
+}
+
+func (m *MockCrudExternalLinkClient) Do(req *http.Request) (io.ReadCloser, error) {
+	response, err := ioutil.ReadFile("./fixtures/create-extlink-response.json")
+	if err != nil {
+		m.T.Fatal(err)
+	}
+	if req.Method != m.method {
+		m.T.Errorf("request method expected '%s' got '%s'", m.method, req.Method)
+	}
+	body, _ := ioutil.ReadAll(req.Body)
+	link := ExternalLink{}
+	err = json.Unmarshal(body, &link)
+	if err != nil {
+		m.T.Fatal(err)
+	}
+	return ioutil.NopCloser(bytes.NewReader(response)), nil
+}
+
+func TestExternalLinks_CreateUpdateDeleteExternalLink(t *testing.T) {
+	baseurl, _ := url.Parse("http://testing.wavefront.com")
+	e := &ExternalLinks{
+		client: &MockCrudExternalLinkClient{
+			Client: Client{
+				Config:     &Config{Token: "1234-5678-9977"},
+				BaseURL:    baseurl,
+				httpClient: http.DefaultClient,
+				debug:      true,
+			},
+			method: "PUT",
+			T:      t,
+		},
+	}
+
+	link := ExternalLink{
+		Name:              "Logs",
+		Description:       "Logs for the source over the chart's time range",
+		Template:          "https://logs.example.com/search?host={{source}}&from={{startEpochMillis}}&to={{endEpochMillis}}",
+		MetricFilterRegex: `^app\..*`,
+		SourceFilterRegex: "^web-.*",
+		PointTagFilterRegexes: map[string]string{
+			"env": "^prod$",
+		},
+	}
+
+	if err := e.Update(&link); err == nil {
+		t.Errorf("expected external link update to error with no ID")
+	}
+
+	e.client.(*MockCrudExternalLinkClient).method = "POST"
+
+	if err := e.Create(&link); err != nil {
+		t.Fatal(err)
+	}
+	if *link.ID != "1234" {
+		t.Errorf("external link ID expected 1234, got %s", *link.ID)
+	}
+	if !link.IsLogIntegration {
+		t.Error("expected IsLogIntegration to be set from the response")
+	}
+
+	e.client.(*MockCrudExternalLinkClient).method = "PUT"
+	if err := e.Update(&link); err != nil {
+		t.Error(err)
+	}
+
+	e.client.(*MockCrudExternalLinkClient).method = "DELETE"
+	if err := e.Delete(&link); err != nil {
+		t.Error(err)
+	}
+
+	if link.ID != nil {
+		t.Error("expected external link ID to be reset after deletion")
+	}
+}
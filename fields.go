@@ -0,0 +1,120 @@
+package wavefront
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// jsonFieldNames returns the lower-cased JSON property names of the fields of
+// struct type t, plus any extra names handled by a custom (un)marshaller.
+// encoding/json matches property names case-insensitively, so these are
+// compared lower-cased too.
+func jsonFieldNames(t reflect.Type, extra ...string) map[string]bool {
+	names := map[string]bool{}
+	for _, n := range extra {
+		names[strings.ToLower(n)] = true
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			for n := range jsonFieldNames(f.Type) {
+				names[n] = true
+			}
+			continue
+		}
+		if f.PkgPath != "" {
+			// unexported
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		names[strings.ToLower(name)] = true
+	}
+	return names
+}
+
+// unknownFields returns the properties of the JSON object b that are not in
+// known, so that they can be sent back to Wavefront unchanged.
+func unknownFields(b []byte, known map[string]bool) (map[string]json.RawMessage, error) {
+	all := map[string]json.RawMessage{}
+	if err := json.Unmarshal(b, &all); err != nil {
+		return nil, err
+	}
+	var unknown map[string]json.RawMessage
+	for k, v := range all {
+		if known[strings.ToLower(k)] {
+			continue
+		}
+		if unknown == nil {
+			unknown = map[string]json.RawMessage{}
+		}
+		unknown[k] = v
+	}
+	return unknown, nil
+}
+
+// withUnknownFields adds the unknown properties to the JSON object b
+func withUnknownFields(b []byte, unknown map[string]json.RawMessage) ([]byte, error) {
+	if len(unknown) == 0 {
+		return b, nil
+	}
+	all := map[string]json.RawMessage{}
+	if err := json.Unmarshal(b, &all); err != nil {
+		return nil, err
+	}
+	for k, v := range unknown {
+		if _, ok := all[k]; !ok {
+			all[k] = v
+		}
+	}
+	return json.Marshal(all)
+}
+
+// unmarshalKnown decodes b into v, which must be a pointer to an alias type
+// without custom unmarshalling, and returns the properties not in known
+func unmarshalKnown(b []byte, v interface{}, known map[string]bool) (map[string]json.RawMessage, error) {
+	if err := json.Unmarshal(b, v); err != nil {
+		return nil, err
+	}
+	return unknownFields(b, known)
+}
+
+// marshalKnown encodes v, which must be a pointer to an alias type without
+// custom marshalling, adding back the unknown properties
+func marshalKnown(v interface{}, unknown map[string]json.RawMessage) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return withUnknownFields(b, unknown)
+}
+
+// idList decodes a list of entities, such as the roles of a user group, into
+// their IDs. Wavefront returns either the IDs or the full entities.
+func idList(raw json.RawMessage) ([]string, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	var ids []string
+	if err := json.Unmarshal(raw, &ids); err == nil {
+		return ids, nil
+	}
+	var entities []struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(raw, &entities); err != nil {
+		return nil, err
+	}
+	ids = make([]string, 0, len(entities))
+	for _, e := range entities {
+		ids = append(ids, e.ID)
+	}
+	return ids, nil
+}
@@ -0,0 +1,59 @@
+package wavefront
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestUnknownFields_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		entity json.Unmarshaler
+		input  string
+	}{
+		{"alert", &Alert{}, `{"name":"a","processRateMinutes":5,"snoozed":-1,"includeObsoleteMetrics":true,"tags":{"customerTags":["x"]}}`},
+		{"dashboard", &Dashboard{}, `{"name":"d","acl":{"canView":["someone"]},"tags":{"customerTags":["x"]}}`},
+		{"event", &Event{}, `{"name":"e","runningState":"ONGOING","annotations":{"severity":"info"}}`},
+		{"derivedmetric", &DerivedMetric{}, `{"name":"m","queryFailing":false,"lastProcessedMillis":1600000000000,"tags":{"customerTags":["x"]}}`},
+		{"cloudintegration", &CloudIntegration{}, `{"name":"c","service":"CLOUDWATCH","cloudWatch":{"namespaces":["AWS/EC2"],"thresholdFilter":{}},"vrops":{}}`},
+		{"extlink", &ExternalLink{}, `{"name":"l","template":"https://example.com","customerId":"example"}`},
+		{"target", &Target{}, `{"title":"t","isHtmlContent":true,"routes":[{"method":"EMAIL"}]}`},
+	}
+
+	for _, test := range tests {
+		if err := json.Unmarshal([]byte(test.input), test.entity); err != nil {
+			t.Fatalf("%s: %s", test.name, err)
+		}
+		out, err := json.Marshal(test.entity)
+		if err != nil {
+			t.Fatalf("%s: %s", test.name, err)
+		}
+
+		in, got := map[string]interface{}{}, map[string]interface{}{}
+		json.Unmarshal([]byte(test.input), &in)
+		json.Unmarshal(out, &got)
+		for k, v := range in {
+			if _, ok := got[k]; !ok {
+				t.Errorf("%s: property %s (%v) lost on round-trip", test.name, k, v)
+			}
+		}
+	}
+}
+
+func TestUnknownFields_KnownFieldsNotResurrected(t *testing.T) {
+	alert := &Alert{}
+	json.Unmarshal([]byte(`{"name":"a","target":"old@example.com","processRateMinutes":5}`), alert)
+
+	// target is modelled, with omitempty, so clearing it must not restore it
+	alert.Target = ""
+	out, _ := json.Marshal(alert)
+
+	got := map[string]interface{}{}
+	json.Unmarshal(out, &got)
+	if _, ok := got["target"]; ok {
+		t.Errorf("cleared target was restored from unknown fields: %s", out)
+	}
+	if got["processRateMinutes"] != float64(5) {
+		t.Errorf("expected processRateMinutes to be kept, got %v", got["processRateMinutes"])
+	}
+}
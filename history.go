@@ -0,0 +1,76 @@
+package wavefront
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"strconv"
+)
+
+// HistoryEntry is a single change in the version history of an Alert or
+// Dashboard
+type HistoryEntry struct {
+	// ID is the ID of the Alert or Dashboard
+	ID string `json:"id"`
+
+	// Version is the version created by the change
+	Version int64 `json:"version"`
+
+	// UpdateUser is the user that made the change
+	UpdateUser string `json:"updateUser"`
+
+	// UpdateTime is the time, in epoch millis, at which the change was made
+	UpdateTime int64 `json:"updateTime"`
+
+	// ChangeDescription describes the change
+	ChangeDescription []string `json:"changeDescription"`
+
+	// InTrash is true if the change was made while in the trash
+	InTrash bool `json:"inTrash"`
+}
+
+// historyPageSize is the number of history entries requested at a time
+const historyPageSize = 100
+
+// listHistory returns every entry of the history endpoint at path, newest
+// first, following pagination
+func listHistory(ctx context.Context, client Wavefronter, path string) ([]*HistoryEntry, error) {
+	var entries []*HistoryEntry
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		params := map[string]string{
+			"offset": strconv.Itoa(len(entries)),
+			"limit":  strconv.Itoa(historyPageSize),
+		}
+		req, err := newRequestWithContext(ctx, client, "GET", path, &params, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		body, err := ioutil.ReadAll(resp)
+		resp.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		page := struct {
+			Response struct {
+				Items     []*HistoryEntry `json:"items"`
+				MoreItems bool            `json:"moreItems"`
+			} `json:"response"`
+		}{}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, err
+		}
+		entries = append(entries, page.Response.Items...)
+		if !page.Response.MoreItems || len(page.Response.Items) == 0 {
+			return entries, nil
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package wavefront
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+type MockHistoryClient struct {
+	Client
+	InvokedCount int
+	T            *testing.T
+}
+
+func (m *MockHistoryClient) Do(req *http.Request) (io.ReadCloser, error) {
+	if req.URL.Path != "/api/v2/alert/1234/history" {
+		m.T.Errorf("unexpected path %s", req.URL.Path)
+	}
+	if offset := req.URL.Query().Get("offset"); offset != fmt.Sprint(2*m.InvokedCount) {
+		m.T.Errorf("offset, expected %d, got %s", 2*m.InvokedCount, offset)
+	}
+	response, err := ioutil.ReadFile(fmt.Sprintf("./fixtures/history-alert-%d.json", m.InvokedCount))
+	if err != nil {
+		m.T.Fatal(err)
+	}
+	m.InvokedCount++
+	return ioutil.NopCloser(bytes.NewReader(response)), nil
+}
+
+func TestAlerts_History(t *testing.T) {
+	baseurl, _ := url.Parse("http://testing.wavefront.com")
+	a := &Alerts{
+		client: &MockHistoryClient{
+			Client: Client{
+				Config:     &Config{Token: "1234-5678-9977"},
+				BaseURL:    baseurl,
+				httpClient: http.DefaultClient,
+			},
+			T: t,
+		},
+	}
+
+	if _, err := a.History(&Alert{}); err == nil {
+		t.Error("expected alert history to error with no ID")
+	}
+
+	id := "1234"
+	history, err := a.History(&Alert{ID: &id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 3 {
+		t.Fatalf("expected 3 history entries, got %d", len(history))
+	}
+	if history[0].Version != 3 || history[2].Version != 1 {
+		t.Errorf("expected versions newest first, got %d...%d", history[0].Version, history[2].Version)
+	}
+	if history[2].UpdateUser != "other@example.com" || history[2].ChangeDescription[0] != "Alert created" {
+		t.Errorf("unexpected history entry %+v", history[2])
+	}
+}
+
+type MockVersionClient struct {
+	Client
+	requests []string
+	T        *testing.T
+}
+
+func (m *MockVersionClient) Do(req *http.Request) (io.ReadCloser, error) {
+	m.requests = append(m.requests, req.Method+" "+req.URL.Path)
+	if req.Method == "PUT" {
+		// the update is echoed back, as Wavefront does
+		body, _ := ioutil.ReadAll(req.Body)
+		return ioutil.NopCloser(bytes.NewReader([]byte(`{"response":` + string(body) + `}`))), nil
+	}
+	// historical versions are returned without their ID
+	return ioutil.NopCloser(bytes.NewReader([]byte(`{"response":{"name":"version 2"}}`))), nil
+}
+
+func TestAlertsAndDashboards_VersionPaths(t *testing.T) {
+	baseurl, _ := url.Parse("http://testing.wavefront.com")
+	client := &MockVersionClient{
+		Client: Client{
+			Config:     &Config{Token: "1234-5678-9977"},
+			BaseURL:    baseurl,
+			httpClient: http.DefaultClient,
+		},
+		T: t,
+	}
+
+	id := "1234"
+	alerts := &Alerts{client: client}
+	if err := alerts.Revert(&Alert{}, 2); err == nil {
+		t.Error("expected alert revert to error with no ID")
+	}
+	alert := &Alert{ID: &id, Name: "version 3"}
+	if err := alerts.GetVersion(&Alert{ID: &id}, 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := alerts.Revert(alert, 2); err != nil {
+		t.Fatal(err)
+	}
+	if alert.ID == nil || *alert.ID != "1234" || alert.Name != "version 2" {
+		t.Errorf("expected alert 1234 to be reverted, got %+v", alert)
+	}
+
+	dashboards := &Dashboards{client: client}
+	if err := dashboards.Revert(&Dashboard{}, 2); err == nil {
+		t.Error("expected dashboard revert to error with no ID")
+	}
+	dashboard := &Dashboard{ID: "my-dash", Name: "version 3"}
+	if err := dashboards.Revert(dashboard, 2); err != nil {
+		t.Fatal(err)
+	}
+	if dashboard.ID != "my-dash" || dashboard.Name != "version 2" {
+		t.Errorf("expected dashboard my-dash to be reverted, got %+v", dashboard)
+	}
+
+	expected := []string{
+		"GET /api/v2/alert/1234/2",
+		"GET /api/v2/alert/1234/2",
+		"PUT /api/v2/alert/1234",
+		"GET /api/v2/dashboard/my-dash/2",
+		"PUT /api/v2/dashboard/my-dash",
+	}
+	if fmt.Sprint(client.requests) != fmt.Sprint(expected) {
+		t.Errorf("requests expected %v, got %v", expected, client.requests)
+	}
+}
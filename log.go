@@ -0,0 +1,177 @@
+package wavefront
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	defaultMaxLogBodySize = 4096
+	redacted              = "REDACTED"
+)
+
+// alwaysRedactedHeaders are never logged, regardless of LogConfig
+var alwaysRedactedHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// Logger receives an entry for every request attempt made by a Client.
+type Logger interface {
+	LogRequest(entry *RequestLog)
+}
+
+// LoggerFunc adapts an ordinary function to a Logger
+type LoggerFunc func(entry *RequestLog)
+
+// LogRequest calls f(entry)
+func (f LoggerFunc) LogRequest(entry *RequestLog) {
+	f(entry)
+}
+
+// LogConfig configures logging of requests made by a Client.
+type LogConfig struct {
+	// Logger receives the log entries. Use NewStdLogger to write to a *log.Logger.
+	Logger Logger
+
+	// RedactHeaders are extra headers whose values are replaced before logging.
+	// Authorization, Proxy-Authorization, Cookie and Set-Cookie are always redacted.
+	RedactHeaders []string
+
+	// LogBodies enables logging of request and response bodies
+	LogBodies bool
+
+	// MaxBodySize is the number of bytes of each body that will be logged.
+	// Defaults to 4096.
+	MaxBodySize int
+}
+
+// RequestLog describes a single request attempt and its outcome.
+// Sensitive headers have already been redacted.
+type RequestLog struct {
+	Method         string
+	URL            string
+	Attempt        int
+	RequestHeader  http.Header
+	RequestBody    []byte
+	StatusCode     int
+	ResponseHeader http.Header
+	ResponseBody   []byte
+
+	// ResponseSize is the length of the response body, or -1 if unknown
+	ResponseSize int64
+
+	// Duration is the time taken to receive the response headers
+	Duration time.Duration
+
+	// Err is the transport error, if the request failed to complete
+	Err error
+}
+
+// NewStdLogger returns a Logger that writes one line per request, in
+// key=value form, to l.
+func NewStdLogger(l *log.Logger) Logger {
+	return LoggerFunc(func(e *RequestLog) {
+		var b strings.Builder
+		fmt.Fprintf(&b, "method=%s url=%q attempt=%d", e.Method, e.URL, e.Attempt)
+		if e.Err != nil {
+			fmt.Fprintf(&b, " duration=%s error=%q", e.Duration, e.Err.Error())
+		} else {
+			fmt.Fprintf(&b, " status=%d duration=%s size=%d", e.StatusCode, e.Duration, e.ResponseSize)
+		}
+		for k, v := range e.RequestHeader {
+			fmt.Fprintf(&b, " req.%s=%q", k, strings.Join(v, ","))
+		}
+		if e.RequestBody != nil {
+			fmt.Fprintf(&b, " req.body=%q", e.RequestBody)
+		}
+		if e.ResponseBody != nil {
+			fmt.Fprintf(&b, " resp.body=%q", e.ResponseBody)
+		}
+		l.Print(b.String())
+	})
+}
+
+// logConfig returns the logging configuration in effect for the client.
+// Debug mode logs to stdout, with bodies, when no Logger is configured.
+func (c Client) logConfig() *LogConfig {
+	if c.Config.Logging != nil && c.Config.Logging.Logger != nil {
+		return c.Config.Logging
+	}
+	if c.debug == true {
+		return &LogConfig{
+			Logger:    NewStdLogger(log.New(os.Stdout, "wavefront: ", log.LstdFlags)),
+			LogBodies: true,
+		}
+	}
+	return nil
+}
+
+// logRequest records an attempt. If bodies are logged the response body is
+// replaced with one that replays the bytes read for logging.
+func (lc *LogConfig) logRequest(req *http.Request, attempt int, resp *http.Response, err error, d time.Duration) {
+	entry := &RequestLog{
+		Method:        req.Method,
+		URL:           req.URL.String(),
+		Attempt:       attempt,
+		RequestHeader: lc.redact(req.Header),
+		Duration:      d,
+		Err:           err,
+	}
+
+	if lc.LogBodies && req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			entry.RequestBody, _ = ioutil.ReadAll(io.LimitReader(body, int64(lc.maxBodySize())))
+			body.Close()
+		}
+	}
+
+	if resp != nil {
+		entry.StatusCode = resp.StatusCode
+		entry.ResponseHeader = lc.redact(resp.Header)
+		entry.ResponseSize = resp.ContentLength
+		if lc.LogBodies {
+			head, _ := ioutil.ReadAll(io.LimitReader(resp.Body, int64(lc.maxBodySize())))
+			entry.ResponseBody = head
+			resp.Body = struct {
+				io.Reader
+				io.Closer
+			}{io.MultiReader(bytes.NewReader(head), resp.Body), resp.Body}
+		}
+	}
+
+	lc.Logger.LogRequest(entry)
+}
+
+func (lc *LogConfig) maxBodySize() int {
+	if lc.MaxBodySize <= 0 {
+		return defaultMaxLogBodySize
+	}
+	return lc.MaxBodySize
+}
+
+// redact returns a copy of h with sensitive header values replaced
+func (lc *LogConfig) redact(h http.Header) http.Header {
+	return redactHeader(h, lc.RedactHeaders)
+}
+
+// redactHeader returns a copy of h with the values of alwaysRedactedHeaders
+// and of the named extra headers replaced
+func redactHeader(h http.Header, extra []string) http.Header {
+	out := make(http.Header, len(h))
+	for k, v := range h {
+		out[k] = v
+	}
+	for _, names := range [][]string{alwaysRedactedHeaders, extra} {
+		for _, name := range names {
+			if out.Get(name) != "" {
+				out.Set(name, redacted)
+			}
+		}
+	}
+	return out
+}
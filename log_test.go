@@ -0,0 +1,83 @@
+package wavefront
+
+import (
+	"bytes"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClientLogging(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"response":"a long response body"}`))
+	}))
+	defer srv.Close()
+
+	var entries []*RequestLog
+	client, err := NewClient(&Config{
+		Address:       strings.TrimLeft(srv.URL, "https://"),
+		Token:         "123456789",
+		SkipTLSVerify: true,
+		Logging: &LogConfig{
+			Logger:        LoggerFunc(func(e *RequestLog) { entries = append(entries, e) }),
+			RedactHeaders: []string{"X-Secret"},
+			LogBodies:     true,
+			MaxBodySize:   10,
+		},
+	})
+	if err != nil {
+		t.Fatal("error initiating client:", err)
+	}
+
+	req, _ := client.NewRequest("POST", "test/thing", nil, []byte(`{ "some" : "json" }`))
+	req.Header.Set("X-Secret", "hunter2")
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal("error executing request:", err)
+	}
+	body, _ := ioutil.ReadAll(resp)
+	resp.Close()
+
+	if string(body) != `{"response":"a long response body"}` {
+		t.Errorf("expected full response body after logging, got %s", body)
+	}
+
+	if len(entries) != 1 {
+		t.Fatalf("log entries, expected 1, got %d", len(entries))
+	}
+	e := entries[0]
+	if e.Method != "POST" || e.StatusCode != 200 || e.Attempt != 1 {
+		t.Errorf("unexpected entry: %s %d attempt %d", e.Method, e.StatusCode, e.Attempt)
+	}
+	if e.RequestHeader.Get("Authorization") != "REDACTED" || e.RequestHeader.Get("X-Secret") != "REDACTED" {
+		t.Errorf("expected sensitive headers to be redacted, got %v", e.RequestHeader)
+	}
+	if req.Header.Get("Authorization") != "Bearer 123456789" {
+		t.Error("expected request headers not to be modified by redaction")
+	}
+	if string(e.RequestBody) != `{ "some" :` || string(e.ResponseBody) != `{"response` {
+		t.Errorf("expected bodies capped at 10 bytes, got %q and %q", e.RequestBody, e.ResponseBody)
+	}
+}
+
+func TestStdLogger(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewStdLogger(log.New(&buf, "", 0))
+	lc := &LogConfig{Logger: l}
+
+	req, _ := http.NewRequest("GET", "https://example.wavefront.com/api/v2/alert", nil)
+	req.Header.Set("Authorization", "Bearer 123456789")
+	lc.logRequest(req, 1, &http.Response{StatusCode: 404, ContentLength: 12, Body: ioutil.NopCloser(&bytes.Buffer{})}, nil, 0)
+
+	out := buf.String()
+	if strings.Contains(out, "123456789") {
+		t.Errorf("token leaked into log output: %s", out)
+	}
+	if !strings.Contains(out, "status=404") || !strings.Contains(out, "size=12") {
+		t.Errorf("expected status and size in log output: %s", out)
+	}
+}
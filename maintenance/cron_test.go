@@ -0,0 +1,108 @@
+package maintenance
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCronNext(t *testing.T) {
+	// a Monday
+	from := time.Date(2020, 9, 21, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"0 2 * * TUE", time.Date(2020, 9, 22, 2, 0, 0, 0, time.UTC)},
+		{"0 2 * * 2", time.Date(2020, 9, 22, 2, 0, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2020, 9, 21, 10, 45, 0, 0, time.UTC)},
+		{"30 10 * * *", time.Date(2020, 9, 22, 10, 30, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2020, 10, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 9-17/4 * * MON-FRI", time.Date(2020, 9, 21, 13, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2020, 9, 27, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 feb *", time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		// either day field matches when both are restricted
+		{"0 0 25 * SUN", time.Date(2020, 9, 25, 0, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2020, 9, 27, 0, 0, 0, 0, time.UTC)},
+		{"0 0 30 2 *", time.Time{}},
+	}
+
+	for _, test := range tests {
+		s, err := parseCron(test.expr)
+		if err != nil {
+			t.Errorf("%s: %s", test.expr, err)
+			continue
+		}
+		if got := s.next(from); !got.Equal(test.want) {
+			t.Errorf("%s: expected %s, got %s", test.expr, test.want, got)
+		}
+	}
+}
+
+func TestCronNext_Location(t *testing.T) {
+	loc := time.FixedZone("UTC+10", 10*60*60)
+	s, _ := parseCron("0 2 * * *")
+
+	got := s.next(time.Date(2020, 9, 21, 0, 0, 0, 0, time.UTC).In(loc))
+	if want := time.Date(2020, 9, 21, 16, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+}
+
+func TestParseCron_Invalid(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"* * * * FOO",
+	} {
+		if _, err := parseCron(expr); err == nil {
+			t.Errorf("expected an error parsing %q", expr)
+		}
+	}
+}
+
+func TestParseRRule(t *testing.T) {
+	from := time.Date(2020, 9, 21, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		rule string
+		want time.Time
+	}{
+		{"FREQ=WEEKLY;BYDAY=TU;BYHOUR=2", time.Date(2020, 9, 22, 2, 0, 0, 0, time.UTC)},
+		{"RRULE:FREQ=WEEKLY;BYDAY=SA,SU;BYHOUR=22;BYMINUTE=30", time.Date(2020, 9, 26, 22, 30, 0, 0, time.UTC)},
+		{"FREQ=DAILY;BYHOUR=1", time.Date(2020, 9, 22, 1, 0, 0, 0, time.UTC)},
+		{"FREQ=HOURLY", time.Date(2020, 9, 21, 11, 0, 0, 0, time.UTC)},
+		{"FREQ=MONTHLY;BYMONTHDAY=15;BYHOUR=3", time.Date(2020, 10, 15, 3, 0, 0, 0, time.UTC)},
+	}
+
+	for _, test := range tests {
+		s, err := parseRRule(test.rule)
+		if err != nil {
+			t.Errorf("%s: %s", test.rule, err)
+			continue
+		}
+		if got := s.next(from); !got.Equal(test.want) {
+			t.Errorf("%s: expected %s, got %s", test.rule, test.want, got)
+		}
+	}
+
+	for _, rule := range []string{
+		"FREQ=YEARLY",
+		"FREQ=WEEKLY",
+		"FREQ=DAILY;INTERVAL=2",
+		"FREQ=DAILY;COUNT=3",
+		"FREQ=MONTHLY;BYDAY=1MO",
+		"FREQ=MONTHLY;BYMONTHDAY=1;BYDAY=MO",
+	} {
+		if _, err := parseRRule(rule); err == nil {
+			t.Errorf("expected an error parsing %q", rule)
+		}
+	}
+}
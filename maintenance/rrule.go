@@ -0,0 +1,85 @@
+package maintenance
+
+import (
+	"fmt"
+	"strings"
+)
+
+var rruleDays = map[string]string{
+	"SU": "0", "MO": "1", "TU": "2", "WE": "3", "TH": "4", "FR": "5", "SA": "6",
+}
+
+// parseRRule parses the subset of RFC 5545 recurrence rules that can be
+// expressed as a cron schedule, e.g. "FREQ=WEEKLY;BYDAY=TU;BYHOUR=2".
+// FREQ may be HOURLY, DAILY, WEEKLY or MONTHLY, narrowed by BYMONTH,
+// BYMONTHDAY, BYDAY (without ordinals), BYHOUR and BYMINUTE. There is no
+// DTSTART, so unset BYHOUR and BYMINUTE default to 0. INTERVAL, COUNT, UNTIL
+// and the other parts are not supported.
+func parseRRule(rule string) (*cronSchedule, error) {
+	parts := map[string]string{}
+	for _, p := range strings.Split(strings.TrimPrefix(rule, "RRULE:"), ";") {
+		kv := strings.SplitN(p, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid RRULE %q: bad part %q", rule, p)
+		}
+		parts[strings.ToUpper(kv[0])] = strings.ToUpper(kv[1])
+	}
+
+	field := func(name, def string) string {
+		if v, ok := parts[name]; ok {
+			delete(parts, name)
+			return v
+		}
+		return def
+	}
+	freq := field("FREQ", "")
+	if interval := field("INTERVAL", "1"); interval != "1" {
+		return nil, fmt.Errorf("invalid RRULE %q: INTERVAL is not supported", rule)
+	}
+	minute := field("BYMINUTE", "0")
+	hour := field("BYHOUR", "")
+	dom := field("BYMONTHDAY", "*")
+	month := field("BYMONTH", "*")
+	dow := "*"
+	if days := field("BYDAY", ""); days != "" {
+		var nums []string
+		for _, d := range strings.Split(days, ",") {
+			n, ok := rruleDays[d]
+			if !ok {
+				return nil, fmt.Errorf("invalid RRULE %q: unsupported BYDAY value %q", rule, d)
+			}
+			nums = append(nums, n)
+		}
+		dow = strings.Join(nums, ",")
+	}
+	if dom != "*" && dow != "*" {
+		// cron would match either, where RRULE requires both
+		return nil, fmt.Errorf("invalid RRULE %q: BYMONTHDAY with BYDAY is not supported", rule)
+	}
+	for name := range parts {
+		return nil, fmt.Errorf("invalid RRULE %q: %s is not supported", rule, name)
+	}
+
+	switch freq {
+	case "HOURLY":
+		if hour == "" {
+			hour = "*"
+		}
+	case "DAILY":
+	case "WEEKLY":
+		if dow == "*" {
+			return nil, fmt.Errorf("invalid RRULE %q: WEEKLY requires BYDAY", rule)
+		}
+	case "MONTHLY":
+		if dom == "*" && dow == "*" {
+			return nil, fmt.Errorf("invalid RRULE %q: MONTHLY requires BYMONTHDAY or BYDAY", rule)
+		}
+	default:
+		return nil, fmt.Errorf("invalid RRULE %q: unsupported FREQ %q", rule, freq)
+	}
+
+	if hour == "" {
+		hour = "0"
+	}
+	return parseCron(strings.Join([]string{minute, hour, dom, month, dow}, " "))
+}
@@ -0,0 +1,256 @@
+// Package maintenance creates recurring Wavefront maintenance windows.
+//
+// Wavefront maintenance windows are one-off. A Scheduler turns recurrence
+// rules, such as "every Tuesday 02:00-04:00 UTC for sources tagged db", into
+// concrete windows, keeping the next few occurrences of each rule created
+// ahead of time and deleting them once they have ended:
+//
+//	scheduler, err := maintenance.NewScheduler(client.MaintenanceWindows(), maintenance.Recurrence{
+//		Name:     "db-patching",
+//		Schedule: "0 2 * * TUE",
+//		Duration: 2 * time.Hour,
+//		Template: wavefront.MaintenanceWindow{
+//			Title:                "DB patching",
+//			Reason:               "Weekly patching",
+//			RelevantCustomerTags: []string{"db"},
+//			RelevantHostTags:     []string{"db"},
+//		},
+//	})
+//	...
+//	err = scheduler.Run(ctx)
+package maintenance
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	wavefront "github.com/spaceapegames/go-wavefront"
+)
+
+// Service is the part of *wavefront.MaintenanceWindows used by a Scheduler
+type Service interface {
+	FindContext(ctx context.Context, filter []*wavefront.SearchCondition) ([]*wavefront.MaintenanceWindow, error)
+	CreateContext(ctx context.Context, window *wavefront.MaintenanceWindow) error
+	DeleteContext(ctx context.Context, window *wavefront.MaintenanceWindow) error
+}
+
+// Clock is the source of time for a Scheduler, so that tests can control it
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time                         { return time.Now() }
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+const (
+	defaultLookahead = 4
+	defaultInterval  = time.Hour
+)
+
+// Recurrence is a maintenance window that repeats on a schedule
+type Recurrence struct {
+	// Name identifies the Recurrence. It is recorded in the Reason of every
+	// window created for it, as "[schedule:Name]", which is how the windows
+	// are found again, so it must be unique and must not change.
+	Name string
+
+	// Schedule gives the start time of each occurrence, either as a
+	// five-field cron expression (e.g. "0 2 * * TUE") or as an RRULE
+	// (e.g. "FREQ=WEEKLY;BYDAY=TU;BYHOUR=2")
+	Schedule string
+
+	// Location is the time zone that Schedule is evaluated in.
+	// Defaults to UTC.
+	Location *time.Location
+
+	// Duration is the length of each occurrence
+	Duration time.Duration
+
+	// Template is the maintenance window created for each occurrence. Its
+	// start and end times are set from Schedule and Duration.
+	Template wavefront.MaintenanceWindow
+}
+
+// recurrence is a Recurrence with its schedule parsed
+type recurrence struct {
+	Recurrence
+	schedule *cronSchedule
+	marker   string
+}
+
+// Scheduler creates and deletes maintenance windows for a set of Recurrences
+type Scheduler struct {
+	// Lookahead is the number of upcoming occurrences of each Recurrence
+	// that are kept created. Defaults to 4.
+	Lookahead int
+
+	// Interval is how often Run reconciles. Defaults to an hour.
+	Interval time.Duration
+
+	// Retention is how long ended windows are kept before they are deleted.
+	// Defaults to 0, deleting them as soon as they end.
+	Retention time.Duration
+
+	// Clock defaults to the system clock
+	Clock Clock
+
+	// ErrorHandler, if set, is called with any error from a reconciliation
+	// made by Run. Run carries on regardless.
+	ErrorHandler func(error)
+
+	service     Service
+	recurrences []*recurrence
+}
+
+// NewScheduler returns a Scheduler maintaining windows for the given
+// Recurrences using service, which is usually client.MaintenanceWindows()
+func NewScheduler(service Service, recurrences ...Recurrence) (*Scheduler, error) {
+	s := &Scheduler{service: service}
+	names := map[string]bool{}
+	for _, r := range recurrences {
+		if r.Name == "" || strings.ContainsAny(r.Name, "[]") {
+			return nil, fmt.Errorf("invalid recurrence name %q", r.Name)
+		}
+		if names[r.Name] {
+			return nil, fmt.Errorf("duplicate recurrence name %q", r.Name)
+		}
+		names[r.Name] = true
+		if r.Duration <= 0 {
+			return nil, fmt.Errorf("recurrence %s: duration must be positive", r.Name)
+		}
+
+		var sched *cronSchedule
+		var err error
+		if strings.Contains(strings.ToUpper(r.Schedule), "FREQ=") {
+			sched, err = parseRRule(r.Schedule)
+		} else {
+			sched, err = parseCron(r.Schedule)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("recurrence %s: %s", r.Name, err)
+		}
+		if r.Location == nil {
+			r.Location = time.UTC
+		}
+
+		s.recurrences = append(s.recurrences, &recurrence{
+			Recurrence: r,
+			schedule:   sched,
+			marker:     fmt.Sprintf("[schedule:%s]", r.Name),
+		})
+	}
+	return s, nil
+}
+
+func (s *Scheduler) clock() Clock {
+	if s.Clock == nil {
+		return systemClock{}
+	}
+	return s.Clock
+}
+
+// Run reconciles immediately and then every Interval until ctx is done,
+// when it returns ctx.Err()
+func (s *Scheduler) Run(ctx context.Context) error {
+	interval := s.Interval
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+	for {
+		if err := s.Reconcile(ctx); err != nil && ctx.Err() == nil && s.ErrorHandler != nil {
+			s.ErrorHandler(err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-s.clock().After(interval):
+		}
+	}
+}
+
+// Reconcile brings the windows of every Recurrence up to date: the next
+// Lookahead occurrences are created if they don't exist, duplicate windows
+// and future windows no longer on the schedule are deleted, and windows that
+// ended more than Retention ago are deleted. A window that is in progress is
+// never deleted. All Recurrences are attempted; the first error is returned.
+func (s *Scheduler) Reconcile(ctx context.Context) error {
+	var first error
+	for _, r := range s.recurrences {
+		if err := s.reconcile(ctx, r); err != nil && first == nil {
+			first = fmt.Errorf("recurrence %s: %s", r.Name, err)
+		}
+	}
+	return first
+}
+
+func (s *Scheduler) reconcile(ctx context.Context, r *recurrence) error {
+	now := s.clock().Now()
+	lookahead := s.Lookahead
+	if lookahead <= 0 {
+		lookahead = defaultLookahead
+	}
+
+	// the upcoming occurrences, including one in progress
+	planned := map[int64]bool{}
+	var starts []time.Time
+	t := now.Add(-r.Duration).In(r.Location)
+	for len(starts) < lookahead {
+		t = r.schedule.next(t)
+		if t.IsZero() {
+			break
+		}
+		starts = append(starts, t)
+		planned[t.Unix()] = false
+	}
+
+	found, err := s.service.FindContext(ctx, []*wavefront.SearchCondition{
+		{Key: "reason", Value: r.marker, MatchingMethod: "CONTAINS"},
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, w := range found {
+		if !strings.Contains(w.Reason, r.marker) {
+			continue
+		}
+		start, end := time.Unix(w.StartTimeInSeconds, 0), time.Unix(w.EndTimeInSeconds, 0)
+
+		var remove bool
+		switch exists, ok := planned[w.StartTimeInSeconds]; {
+		case !end.After(now):
+			remove = now.Sub(end) >= s.Retention
+		case ok && !exists:
+			planned[w.StartTimeInSeconds] = true
+		case start.After(now):
+			// a duplicate, or an occurrence no longer on the schedule
+			remove = true
+		}
+		if remove {
+			if err := s.service.DeleteContext(ctx, w); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, start := range starts {
+		if planned[start.Unix()] {
+			continue
+		}
+		window := r.Template
+		window.ID = nil
+		window.RunningState = ""
+		window.Reason = strings.TrimSpace(r.Template.Reason + " " + r.marker)
+		window.StartTimeInSeconds = start.Unix()
+		window.EndTimeInSeconds = start.Add(r.Duration).Unix()
+		if err := s.service.CreateContext(ctx, &window); err != nil {
+			return err
+		}
+	}
+	return nil
+}
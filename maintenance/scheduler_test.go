@@ -0,0 +1,276 @@
+package maintenance
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	wavefront "github.com/spaceapegames/go-wavefront"
+)
+
+// fakeService is an in-memory Service
+type fakeService struct {
+	windows map[string]*wavefront.MaintenanceWindow
+	nextID  int
+	created int
+	deleted int
+}
+
+func newFakeService() *fakeService {
+	return &fakeService{windows: map[string]*wavefront.MaintenanceWindow{}}
+}
+
+func (f *fakeService) FindContext(ctx context.Context, filter []*wavefront.SearchCondition) ([]*wavefront.MaintenanceWindow, error) {
+	var results []*wavefront.MaintenanceWindow
+	for _, w := range f.windows {
+		if strings.Contains(w.Reason, filter[0].Value) {
+			c := *w
+			results = append(results, &c)
+		}
+	}
+	return results, nil
+}
+
+func (f *fakeService) CreateContext(ctx context.Context, window *wavefront.MaintenanceWindow) error {
+	f.nextID++
+	id := fmt.Sprint(f.nextID)
+	window.ID = &id
+	c := *window
+	f.windows[id] = &c
+	f.created++
+	return nil
+}
+
+func (f *fakeService) DeleteContext(ctx context.Context, window *wavefront.MaintenanceWindow) error {
+	delete(f.windows, *window.ID)
+	window.ID = nil
+	f.deleted++
+	return nil
+}
+
+func (f *fakeService) starts() map[int64]int {
+	starts := map[int64]int{}
+	for _, w := range f.windows {
+		starts[w.StartTimeInSeconds]++
+	}
+	return starts
+}
+
+type fakeClock struct {
+	mu    sync.Mutex
+	now   time.Time
+	after chan time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time { return c.after }
+
+func tuesdayPatching() Recurrence {
+	return Recurrence{
+		Name:     "db-patching",
+		Schedule: "0 2 * * TUE",
+		Duration: 2 * time.Hour,
+		Template: wavefront.MaintenanceWindow{
+			Title:                "DB patching",
+			Reason:               "Weekly patching",
+			RelevantCustomerTags: []string{"db"},
+			RelevantHostTags:     []string{"db"},
+		},
+	}
+}
+
+func TestScheduler_Reconcile(t *testing.T) {
+	service := newFakeService()
+	// Monday 21st September 2020
+	clock := &fakeClock{now: time.Date(2020, 9, 21, 12, 0, 0, 0, time.UTC)}
+	s, err := NewScheduler(service, tuesdayPatching())
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Clock = clock
+	s.Lookahead = 3
+
+	if err := s.Reconcile(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(service.windows) != 3 {
+		t.Fatalf("expected 3 windows, got %d", len(service.windows))
+	}
+	for _, day := range []int{22, 29} {
+		start := time.Date(2020, 9, day, 2, 0, 0, 0, time.UTC).Unix()
+		if service.starts()[start] != 1 {
+			t.Errorf("expected a window starting on %d September", day)
+		}
+	}
+	for _, w := range service.windows {
+		if w.EndTimeInSeconds-w.StartTimeInSeconds != 2*60*60 {
+			t.Errorf("expected a two hour window, got %d seconds", w.EndTimeInSeconds-w.StartTimeInSeconds)
+		}
+		if w.Title != "DB patching" || w.Reason != "Weekly patching [schedule:db-patching]" {
+			t.Errorf("unexpected title %q or reason %q", w.Title, w.Reason)
+		}
+		if len(w.RelevantHostTags) != 1 || w.RelevantHostTags[0] != "db" {
+			t.Errorf("expected template host tags, got %v", w.RelevantHostTags)
+		}
+	}
+
+	// reconciling again creates nothing
+	if err := s.Reconcile(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if service.created != 3 || service.deleted != 0 {
+		t.Errorf("expected no changes, got %d created and %d deleted", service.created, service.deleted)
+	}
+
+	// during the first window nothing changes
+	clock.now = time.Date(2020, 9, 22, 3, 0, 0, 0, time.UTC)
+	s.Reconcile(context.Background())
+	if service.created != 3 || service.deleted != 0 {
+		t.Errorf("expected no changes, got %d created and %d deleted", service.created, service.deleted)
+	}
+
+	// once it has ended, it is deleted and the next occurrence created
+	clock.now = time.Date(2020, 9, 22, 4, 0, 0, 0, time.UTC)
+	s.Reconcile(context.Background())
+	if service.created != 4 || service.deleted != 1 {
+		t.Errorf("expected 1 created and 1 deleted, got %d created and %d deleted", service.created, service.deleted)
+	}
+	if start := time.Date(2020, 10, 13, 2, 0, 0, 0, time.UTC).Unix(); service.starts()[start] != 1 {
+		t.Errorf("expected a window starting on 13 October")
+	}
+	if start := time.Date(2020, 9, 22, 2, 0, 0, 0, time.UTC).Unix(); service.starts()[start] != 0 {
+		t.Errorf("expected the ended window to be deleted")
+	}
+}
+
+func TestScheduler_Retention(t *testing.T) {
+	service := newFakeService()
+	clock := &fakeClock{now: time.Date(2020, 9, 21, 12, 0, 0, 0, time.UTC)}
+	s, _ := NewScheduler(service, tuesdayPatching())
+	s.Clock = clock
+	s.Lookahead = 1
+	s.Retention = 24 * time.Hour
+
+	s.Reconcile(context.Background())
+	clock.now = time.Date(2020, 9, 22, 12, 0, 0, 0, time.UTC)
+	s.Reconcile(context.Background())
+	if service.deleted != 0 || len(service.windows) != 2 {
+		t.Errorf("expected the ended window to be retained, got %d windows", len(service.windows))
+	}
+
+	clock.now = time.Date(2020, 9, 23, 4, 0, 0, 0, time.UTC)
+	s.Reconcile(context.Background())
+	if service.deleted != 1 || len(service.windows) != 1 {
+		t.Errorf("expected the ended window to be deleted, got %d windows", len(service.windows))
+	}
+}
+
+func TestScheduler_RemovesDuplicatesAndStaleWindows(t *testing.T) {
+	service := newFakeService()
+	clock := &fakeClock{now: time.Date(2020, 9, 21, 12, 0, 0, 0, time.UTC)}
+	s, _ := NewScheduler(service, tuesdayPatching())
+	s.Clock = clock
+	s.Lookahead = 2
+
+	tuesday := time.Date(2020, 9, 22, 2, 0, 0, 0, time.UTC)
+	wednesday := time.Date(2020, 9, 23, 2, 0, 0, 0, time.UTC)
+	for _, start := range []time.Time{tuesday, tuesday, wednesday} {
+		service.CreateContext(context.Background(), &wavefront.MaintenanceWindow{
+			Reason:             "[schedule:db-patching]",
+			StartTimeInSeconds: start.Unix(),
+			EndTimeInSeconds:   start.Add(2 * time.Hour).Unix(),
+		})
+	}
+	// a window for another recurrence is left alone
+	service.CreateContext(context.Background(), &wavefront.MaintenanceWindow{
+		Reason:             "[schedule:other]",
+		StartTimeInSeconds: wednesday.Unix(),
+		EndTimeInSeconds:   wednesday.Add(time.Hour).Unix(),
+	})
+
+	if err := s.Reconcile(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	starts := service.starts()
+	if starts[tuesday.Unix()] != 1 {
+		t.Errorf("expected one window on Tuesday, got %d", starts[tuesday.Unix()])
+	}
+	if starts[wednesday.Unix()] != 1 {
+		t.Errorf("expected only the other recurrence's window on Wednesday, got %d", starts[wednesday.Unix()])
+	}
+	if next := tuesday.AddDate(0, 0, 7).Unix(); starts[next] != 1 {
+		t.Errorf("expected a window on the following Tuesday")
+	}
+	if len(service.windows) != 3 {
+		t.Errorf("expected 3 windows, got %d", len(service.windows))
+	}
+}
+
+func TestScheduler_Run(t *testing.T) {
+	service := newFakeService()
+	clock := &fakeClock{
+		now:   time.Date(2020, 9, 21, 12, 0, 0, 0, time.UTC),
+		after: make(chan time.Time),
+	}
+	s, _ := NewScheduler(service, tuesdayPatching())
+	s.Clock = clock
+	s.Lookahead = 1
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- s.Run(ctx) }()
+
+	// each tick is only received once the previous reconciliation is done
+	clock.after <- time.Time{}
+	if service.created != 1 {
+		t.Errorf("expected 1 window created on start, got %d", service.created)
+	}
+	clock.set(time.Date(2020, 9, 22, 5, 0, 0, 0, time.UTC))
+	clock.after <- time.Time{}
+	clock.after <- time.Time{}
+	if service.created != 2 || service.deleted != 1 {
+		t.Errorf("expected the next window after a tick, got %d created and %d deleted", service.created, service.deleted)
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestNewScheduler_Invalid(t *testing.T) {
+	valid := tuesdayPatching()
+
+	noName := valid
+	noName.Name = ""
+	badSchedule := valid
+	badSchedule.Schedule = "every tuesday"
+	noDuration := valid
+	noDuration.Duration = 0
+
+	tests := [][]Recurrence{
+		{noName},
+		{badSchedule},
+		{noDuration},
+		{valid, valid},
+	}
+	for _, recurrences := range tests {
+		if _, err := NewScheduler(newFakeService(), recurrences...); err == nil {
+			t.Errorf("expected an error for %+v", recurrences)
+		}
+	}
+}
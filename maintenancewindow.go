@@ -0,0 +1,231 @@
+package wavefront
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+)
+
+// MaintenanceWindow represents a single Wavefront Maintenance Window, during
+// which alerts matching its tags, hosts or sources will not fire.
+type MaintenanceWindow struct {
+	// ID is the Wavefront-assigned ID of an existing Maintenance Window
+	ID *string `json:"id,omitempty"`
+
+	// Title is the title of the Maintenance Window
+	Title string `json:"title"`
+
+	// Reason is the purpose of the Maintenance Window
+	Reason string `json:"reason"`
+
+	// StartTimeInSeconds is the time, in epoch seconds, at which the
+	// Maintenance Window starts
+	StartTimeInSeconds int64 `json:"startTimeInSeconds"`
+
+	// EndTimeInSeconds is the time, in epoch seconds, at which the
+	// Maintenance Window ends
+	EndTimeInSeconds int64 `json:"endTimeInSeconds"`
+
+	// RelevantCustomerTags are the alert tags that the Maintenance Window applies to
+	RelevantCustomerTags []string `json:"relevantCustomerTags"`
+
+	// RelevantHostTags are the source tags that the Maintenance Window applies to
+	RelevantHostTags []string `json:"relevantHostTags,omitempty"`
+
+	// RelevantHostNames are the sources that the Maintenance Window applies to
+	RelevantHostNames []string `json:"relevantHostNames,omitempty"`
+
+	// RelevantHostTagsAnded, if true, requires a source to have all of the
+	// RelevantHostTags to be in maintenance, rather than any of them
+	RelevantHostTagsAnded bool `json:"relevantHostTagsAnded,omitempty"`
+
+	// HostTagGroupHostNamesGroupAnded, if true, requires a source to match both
+	// the RelevantHostTags and the RelevantHostNames to be in maintenance,
+	// rather than either of them
+	HostTagGroupHostNamesGroupAnded bool `json:"hostTagGroupHostNamesGroupAnded,omitempty"`
+
+	// RunningState is the state of the Maintenance Window, one of ONGOING,
+	// PENDING or ENDED. It is set by Wavefront.
+	RunningState string `json:"runningState,omitempty"`
+
+	CreatorId          string `json:"creatorId,omitempty"`
+	UpdaterId          string `json:"updaterId,omitempty"`
+	CreatedEpochMillis int64  `json:"createdEpochMillis,omitempty"`
+	UpdatedEpochMillis int64  `json:"updatedEpochMillis,omitempty"`
+
+	// unknown holds properties returned by Wavefront that are not modelled
+	// above, so that they survive a Get followed by an Update
+	unknown map[string]json.RawMessage
+}
+
+// MaintenanceWindows is used to perform maintenance window-related operations
+// against the Wavefront API
+type MaintenanceWindows struct {
+	// client is the Wavefront client used to perform maintenance window-related operations
+	client Wavefronter
+}
+
+const baseMaintenanceWindowPath = "/api/v2/maintenancewindow"
+
+// maintenanceWindowFields are the JSON properties modelled by MaintenanceWindow
+var maintenanceWindowFields = jsonFieldNames(reflect.TypeOf(MaintenanceWindow{}))
+
+// UnmarshalJSON is a custom JSON unmarshaller for a MaintenanceWindow, used in
+// order to keep properties that are not modelled by MaintenanceWindow
+func (m *MaintenanceWindow) UnmarshalJSON(b []byte) error {
+	type maintenanceWindow MaintenanceWindow
+	if err := json.Unmarshal(b, (*maintenanceWindow)(m)); err != nil {
+		return err
+	}
+
+	var err error
+	m.unknown, err = unknownFields(b, maintenanceWindowFields)
+	return err
+}
+
+func (m *MaintenanceWindow) MarshalJSON() ([]byte, error) {
+	type maintenanceWindow MaintenanceWindow
+	b, err := json.Marshal((*maintenanceWindow)(m))
+	if err != nil {
+		return nil, err
+	}
+	return withUnknownFields(b, m.unknown)
+}
+
+// MaintenanceWindows is used to return a client for maintenance window-related operations
+func (c *Client) MaintenanceWindows() *MaintenanceWindows {
+	return &MaintenanceWindows{client: c}
+}
+
+// Get is used to retrieve an existing MaintenanceWindow by ID.
+// The ID field must be provided
+func (m MaintenanceWindows) Get(window *MaintenanceWindow) error {
+	return m.GetContext(context.Background(), window)
+}
+
+// GetContext is like Get but carries the given context through the request.
+func (m MaintenanceWindows) GetContext(ctx context.Context, window *MaintenanceWindow) error {
+	if window.ID == nil || *window.ID == "" {
+		return fmt.Errorf("MaintenanceWindow id field is not set")
+	}
+
+	return m.crudMaintenanceWindow(ctx, "GET", fmt.Sprintf("%s/%s", baseMaintenanceWindowPath, *window.ID), window)
+}
+
+// Find returns all maintenance windows filtered by the given search conditions.
+// If filter is nil, all maintenance windows are returned.
+func (m MaintenanceWindows) Find(filter []*SearchCondition) ([]*MaintenanceWindow, error) {
+	return m.FindContext(context.Background(), filter)
+}
+
+// FindContext is like Find but carries the given context through every page
+// of the search. Pagination stops as soon as the context is done.
+func (m MaintenanceWindows) FindContext(ctx context.Context, filter []*SearchCondition) ([]*MaintenanceWindow, error) {
+	search := &Search{
+		client: m.client,
+		Type:   "maintenancewindow",
+		Params: &SearchParams{
+			Conditions: filter,
+		},
+	}
+
+	var results []*MaintenanceWindow
+	moreItems := true
+	for moreItems == true {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		resp, err := search.ExecuteContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		var tmpres []*MaintenanceWindow
+		err = json.Unmarshal(resp.Response.Items, &tmpres)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, tmpres...)
+		moreItems = resp.Response.MoreItems
+		search.Params.Offset = resp.NextOffset
+	}
+
+	return results, nil
+}
+
+// Create is used to create a MaintenanceWindow in Wavefront.
+// If successful, the ID field of the maintenance window will be populated.
+func (m MaintenanceWindows) Create(window *MaintenanceWindow) error {
+	return m.CreateContext(context.Background(), window)
+}
+
+// CreateContext is like Create but carries the given context through the request.
+func (m MaintenanceWindows) CreateContext(ctx context.Context, window *MaintenanceWindow) error {
+	return m.crudMaintenanceWindow(ctx, "POST", baseMaintenanceWindowPath, window)
+}
+
+// Update is used to update an existing MaintenanceWindow.
+// The ID field of the maintenance window must be populated
+func (m MaintenanceWindows) Update(window *MaintenanceWindow) error {
+	return m.UpdateContext(context.Background(), window)
+}
+
+// UpdateContext is like Update but carries the given context through the request.
+func (m MaintenanceWindows) UpdateContext(ctx context.Context, window *MaintenanceWindow) error {
+	if window.ID == nil {
+		return fmt.Errorf("maintenance window id field not set")
+	}
+
+	return m.crudMaintenanceWindow(ctx, "PUT", fmt.Sprintf("%s/%s", baseMaintenanceWindowPath, *window.ID), window)
+}
+
+// Delete is used to delete an existing MaintenanceWindow.
+// The ID field of the maintenance window must be populated
+func (m MaintenanceWindows) Delete(window *MaintenanceWindow) error {
+	return m.DeleteContext(context.Background(), window)
+}
+
+// DeleteContext is like Delete but carries the given context through the request.
+func (m MaintenanceWindows) DeleteContext(ctx context.Context, window *MaintenanceWindow) error {
+	if window.ID == nil {
+		return fmt.Errorf("maintenance window id field not set")
+	}
+
+	err := m.crudMaintenanceWindow(ctx, "DELETE", fmt.Sprintf("%s/%s", baseMaintenanceWindowPath, *window.ID), window)
+	if err != nil {
+		return err
+	}
+
+	//reset the ID field so deletion is not attempted again
+	window.ID = nil
+	return nil
+}
+
+func (m MaintenanceWindows) crudMaintenanceWindow(ctx context.Context, method, path string, window *MaintenanceWindow) error {
+	payload, err := json.Marshal(window)
+	if err != nil {
+		return err
+	}
+	req, err := newRequestWithContext(ctx, m.client, method, path, nil, payload)
+	if err != nil {
+		return err
+	}
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Close()
+
+	body, err := ioutil.ReadAll(resp)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(body, &struct {
+		Response *MaintenanceWindow `json:"response"`
+	}{
+		Response: window,
+	})
+}
@@ -0,0 +1,144 @@
+package wavefront
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+type MockMaintenanceWindowClient struct {
+	Client
+	InvokedCount int
+	T            *testing.T
+}
+
+type MockCrudMaintenanceWindowClient struct {
+	Client
+	method string
+	T      *testing.T
+}
+
+func (m *MockMaintenanceWindowClient) Do(req *http.Request) (io.ReadCloser, error) {
+	response, err := ioutil.ReadFile(fmt.Sprintf("./fixtures/paginated-maintenancewindow-%d.json", m.InvokedCount))
+	if err != nil {
+		m.T.Fatal(err)
+	}
+	if req.URL.Path != "/api/v2/search/maintenancewindow" {
+		m.T.Errorf("search path expected /api/v2/search/maintenancewindow, got %s", req.URL.Path)
+	}
+	body, _ := ioutil.ReadAll(req.Body)
+	search := SearchParams{}
+	err = json.Unmarshal(body, &search)
+	if err != nil {
+		m.T.Fatal(err)
+	}
+	if search.Offset != search.Limit*m.InvokedCount {
+		m.T.Errorf("offset, expected %d, got %d", search.Limit*m.InvokedCount, search.Offset)
+	}
+	m.InvokedCount++
+	return ioutil.NopCloser(bytes.NewReader(response)), nil
+}
+
+func TestMaintenanceWindows_PaginatedFind(t *testing.T) {
+	baseurl, _ := url.Parse("http://testing.wavefront.com")
+	m := &MaintenanceWindows{
+		client: &MockMaintenanceWindowClient{
+			Client: Client{
+				Config:     &Config{Token: "1234-5678-9977"},
+				BaseURL:    baseurl,
+				httpClient: http.DefaultClient,
+				debug:      true,
+			},
+			T: t,
+		},
+	}
+	windows, err := m.Find(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	invoked := ((m.client).(*MockMaintenanceWindowClient)).InvokedCount
+	if invoked != 2 {
+		t.Errorf("paginated search, expected 2, got %d", invoked)
+	}
+
+	if len(windows) != 2 || windows[1].Title != "Window 1" {
+		t.Errorf("unexpected maintenance windows: %+v", windows)
+	}
+}
+
+func (m *MockCrudMaintenanceWindowClient) Do(req *http.Request) (io.ReadCloser, error) {
+	response, err := ioutil.ReadFile("./fixtures/create-maintenancewindow-response.json")
+	if err != nil {
+		m.T.Fatal(err)
+	}
+	if req.Method != m.method {
+		m.T.Errorf("request method expected '%s' got '%s'", m.method, req.Method)
+	}
+	body, _ := ioutil.ReadAll(req.Body)
+	window := MaintenanceWindow{}
+	err = json.Unmarshal(body, &window)
+	if err != nil {
+		m.T.Fatal(err)
+	}
+	return ioutil.NopCloser(bytes.NewReader(response)), nil
+}
+
+func TestMaintenanceWindows_CreateUpdateDeleteMaintenanceWindow(t *testing.T) {
+	baseurl, _ := url.Parse("http://testing.wavefront.com")
+	m := &MaintenanceWindows{
+		client: &MockCrudMaintenanceWindowClient{
+			Client: Client{
+				Config:     &Config{Token: "1234-5678-9977"},
+				BaseURL:    baseurl,
+				httpClient: http.DefaultClient,
+				debug:      true,
+			},
+			method: "PUT",
+			T:      t,
+		},
+	}
+
+	window := MaintenanceWindow{
+		Title:                "Patch Tuesday",
+		Reason:               "Database patching",
+		StartTimeInSeconds:   1600740000,
+		EndTimeInSeconds:     1600747200,
+		RelevantCustomerTags: []string{"db"},
+		RelevantHostTags:     []string{"db"},
+	}
+
+	if err := m.Update(&window); err == nil {
+		t.Errorf("expected maintenance window update to error with no ID")
+	}
+
+	m.client.(*MockCrudMaintenanceWindowClient).method = "POST"
+
+	if err := m.Create(&window); err != nil {
+		t.Fatal(err)
+	}
+	if *window.ID != "1234" {
+		t.Errorf("maintenance window ID expected 1234, got %s", *window.ID)
+	}
+	if window.RunningState != "PENDING" {
+		t.Errorf("running state expected PENDING, got %s", window.RunningState)
+	}
+
+	m.client.(*MockCrudMaintenanceWindowClient).method = "PUT"
+	if err := m.Update(&window); err != nil {
+		t.Error(err)
+	}
+
+	m.client.(*MockCrudMaintenanceWindowClient).method = "DELETE"
+	if err := m.Delete(&window); err != nil {
+		t.Error(err)
+	}
+
+	if window.ID != nil {
+		t.Error("expected maintenance window ID to be reset after deletion")
+	}
+}
@@ -0,0 +1,259 @@
+package wavefront
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"time"
+)
+
+// The statuses a Proxy can have
+const (
+	ProxyActive          = "ACTIVE"
+	ProxyStoppedUnknown  = "STOPPED_UNKNOWN"
+	ProxyStoppedByServer = "STOPPED_BY_SERVER"
+)
+
+// Proxy represents a single Wavefront Proxy, which forwards metrics, e.g.
+// from the writer package, to Wavefront. Proxies register themselves, so they
+// cannot be created through the API.
+type Proxy struct {
+	// ID is the Wavefront-assigned ID of the Proxy
+	ID *string `json:"id,omitempty"`
+
+	// Name is the name of the Proxy. It is changed with Rename.
+	Name string `json:"name"`
+
+	// Version is the version of the Proxy software
+	Version string `json:"version,omitempty"`
+
+	// Hostname is the host the Proxy runs on
+	Hostname string `json:"hostname,omitempty"`
+
+	// LastCheckInTime is the time, in epoch millis, at which the Proxy last
+	// checked in with Wavefront
+	LastCheckInTime int64 `json:"lastCheckInTime,omitempty"`
+
+	// Status is the status of the Proxy, one of the Proxy... constants
+	Status string `json:"status,omitempty"`
+
+	// StatusCause is the reason for the status, if the Proxy is stopped
+	StatusCause string `json:"statusCause,omitempty"`
+
+	LastKnownError string `json:"lastKnownError,omitempty"`
+	LastErrorTime  int64  `json:"lastErrorTime,omitempty"`
+	LastErrorEvent *Event `json:"lastErrorEvent,omitempty"`
+
+	// TimeDrift is the difference, in milliseconds, between the clocks of the
+	// Proxy and Wavefront
+	TimeDrift int64 `json:"timeDrift,omitempty"`
+
+	// BytesLeftForBuffer is the free space, in bytes, left for the Proxy to
+	// buffer data while it cannot forward it
+	BytesLeftForBuffer int64 `json:"bytesLeftForBuffer,omitempty"`
+
+	// BytesPerMinuteForBuffer is the rate, in bytes per minute, at which the
+	// Proxy's buffer is filling
+	BytesPerMinuteForBuffer int64 `json:"bytesPerMinuteForBuffer,omitempty"`
+
+	// LocalQueueSize is the number of tasks queued by the Proxy to be
+	// forwarded
+	LocalQueueSize int64 `json:"localQueueSize,omitempty"`
+
+	// Ephemeral is true if the Proxy is deleted once it stops checking in
+	Ephemeral bool `json:"ephemeral,omitempty"`
+
+	// Shutdown is true if the Proxy has been asked to shut down
+	Shutdown bool `json:"shutdown,omitempty"`
+
+	Deleted    bool   `json:"deleted,omitempty"`
+	InTrash    bool   `json:"inTrash,omitempty"`
+	CustomerId string `json:"customerId,omitempty"`
+}
+
+// Proxies is used to perform proxy-related operations against the Wavefront API
+type Proxies struct {
+	// client is the Wavefront client used to perform proxy-related operations
+	client Wavefronter
+}
+
+const baseProxyPath = "/api/v2/proxy"
+
+// proxyPageSize is the number of proxies requested at a time
+const proxyPageSize = 100
+
+// LastCheckIn returns the time at which the Proxy last checked in with
+// Wavefront, or the zero time if it never has
+func (p *Proxy) LastCheckIn() time.Time {
+	if p.LastCheckInTime == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, p.LastCheckInTime*int64(time.Millisecond))
+}
+
+// IsStale reports whether the Proxy has not checked in with Wavefront within
+// threshold of now
+func (p *Proxy) IsStale(threshold time.Duration, now time.Time) bool {
+	return now.Sub(p.LastCheckIn()) > threshold
+}
+
+// Proxies is used to return a client for proxy-related operations
+func (c *Client) Proxies() *Proxies {
+	return &Proxies{client: c}
+}
+
+// Get is used to retrieve an existing Proxy by ID.
+// The ID field must be provided
+func (p Proxies) Get(proxy *Proxy) error {
+	return p.GetContext(context.Background(), proxy)
+}
+
+// GetContext is like Get but carries the given context through the request.
+func (p Proxies) GetContext(ctx context.Context, proxy *Proxy) error {
+	if proxy.ID == nil || *proxy.ID == "" {
+		return fmt.Errorf("Proxy id field is not set")
+	}
+
+	return p.crudProxy(ctx, "GET", fmt.Sprintf("%s/%s", baseProxyPath, *proxy.ID), nil, proxy)
+}
+
+// List returns every Proxy, following pagination
+func (p Proxies) List() ([]*Proxy, error) {
+	return p.ListContext(context.Background())
+}
+
+// ListContext is like List but carries the given context through every page
+// of the listing. Pagination stops as soon as the context is done.
+func (p Proxies) ListContext(ctx context.Context) ([]*Proxy, error) {
+	var proxies []*Proxy
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		params := map[string]string{
+			"offset": strconv.Itoa(len(proxies)),
+			"limit":  strconv.Itoa(proxyPageSize),
+		}
+		req, err := newRequestWithContext(ctx, p.client, "GET", baseProxyPath, &params, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := p.client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		body, err := ioutil.ReadAll(resp)
+		resp.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		page := struct {
+			Response struct {
+				Items     []*Proxy `json:"items"`
+				MoreItems bool     `json:"moreItems"`
+			} `json:"response"`
+		}{}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, err
+		}
+		proxies = append(proxies, page.Response.Items...)
+		if !page.Response.MoreItems || len(page.Response.Items) == 0 {
+			return proxies, nil
+		}
+	}
+}
+
+// FindStale returns every Proxy which has not checked in with Wavefront
+// within threshold of now
+func (p Proxies) FindStale(threshold time.Duration) ([]*Proxy, error) {
+	return p.FindStaleContext(context.Background(), threshold)
+}
+
+// FindStaleContext is like FindStale but carries the given context through
+// every page of the listing.
+func (p Proxies) FindStaleContext(ctx context.Context, threshold time.Duration) ([]*Proxy, error) {
+	proxies, err := p.ListContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var stale []*Proxy
+	for _, proxy := range proxies {
+		if proxy.IsStale(threshold, now) {
+			stale = append(stale, proxy)
+		}
+	}
+	return stale, nil
+}
+
+// Rename is used to change the name of an existing Proxy, which is updated
+// from the response. The ID field of the proxy must be populated
+func (p Proxies) Rename(proxy *Proxy, name string) error {
+	return p.RenameContext(context.Background(), proxy, name)
+}
+
+// RenameContext is like Rename but carries the given context through the request.
+func (p Proxies) RenameContext(ctx context.Context, proxy *Proxy, name string) error {
+	if proxy.ID == nil {
+		return fmt.Errorf("proxy id field not set")
+	}
+
+	// only the name of a proxy can be changed, so nothing else is sent
+	payload, err := json.Marshal(map[string]string{"name": name})
+	if err != nil {
+		return err
+	}
+	return p.crudProxy(ctx, "PUT", fmt.Sprintf("%s/%s", baseProxyPath, *proxy.ID), payload, proxy)
+}
+
+// Delete is used to delete an existing Proxy. A proxy which is still running
+// registers itself again when it next checks in.
+// The ID field of the proxy must be populated
+func (p Proxies) Delete(proxy *Proxy) error {
+	return p.DeleteContext(context.Background(), proxy)
+}
+
+// DeleteContext is like Delete but carries the given context through the request.
+func (p Proxies) DeleteContext(ctx context.Context, proxy *Proxy) error {
+	if proxy.ID == nil {
+		return fmt.Errorf("proxy id field not set")
+	}
+
+	err := p.crudProxy(ctx, "DELETE", fmt.Sprintf("%s/%s", baseProxyPath, *proxy.ID), nil, proxy)
+	if err != nil {
+		return err
+	}
+
+	//reset the ID field so deletion is not attempted again
+	proxy.ID = nil
+	return nil
+}
+
+func (p Proxies) crudProxy(ctx context.Context, method, path string, payload []byte, proxy *Proxy) error {
+	req, err := newRequestWithContext(ctx, p.client, method, path, nil, payload)
+	if err != nil {
+		return err
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Close()
+
+	body, err := ioutil.ReadAll(resp)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(body, &struct {
+		Response *Proxy `json:"response"`
+	}{
+		Response: proxy,
+	})
+}
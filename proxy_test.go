@@ -0,0 +1,138 @@
+package wavefront
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+)
+
+type MockProxyClient struct {
+	Client
+	InvokedCount int
+	T            *testing.T
+}
+
+type MockCrudProxyClient struct {
+	Client
+	method string
+	body   []byte
+	T      *testing.T
+}
+
+func (m *MockProxyClient) Do(req *http.Request) (io.ReadCloser, error) {
+	response, err := ioutil.ReadFile(fmt.Sprintf("./fixtures/paginated-proxy-%d.json", m.InvokedCount))
+	if err != nil {
+		m.T.Fatal(err)
+	}
+	if req.URL.Path != "/api/v2/proxy" {
+		m.T.Errorf("list path expected /api/v2/proxy, got %s", req.URL.Path)
+	}
+	if offset := req.URL.Query().Get("offset"); offset != strconv.Itoa(m.InvokedCount) {
+		m.T.Errorf("offset, expected %d, got %s", m.InvokedCount, offset)
+	}
+	m.InvokedCount++
+	return ioutil.NopCloser(bytes.NewReader(response)), nil
+}
+
+func TestProxies_PaginatedList(t *testing.T) {
+	baseurl, _ := url.Parse("http://testing.wavefront.com")
+	p := &Proxies{
+		client: &MockProxyClient{
+			Client: Client{
+				Config:     &Config{Token: "1234-5678-9977"},
+				BaseURL:    baseurl,
+				httpClient: http.DefaultClient,
+				debug:      true,
+			},
+			T: t,
+		},
+	}
+	proxies, err := p.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	invoked := ((p.client).(*MockProxyClient)).InvokedCount
+	if invoked != 2 {
+		t.Errorf("paginated list, expected 2, got %d", invoked)
+	}
+
+	if len(proxies) != 2 || proxies[1].Hostname != "proxy-2" {
+		t.Fatalf("unexpected proxies: %+v", proxies)
+	}
+	if proxies[1].Status != ProxyStoppedUnknown || proxies[1].LocalQueueSize != 1200 || !proxies[1].Shutdown {
+		t.Errorf("unexpected proxy: %+v", proxies[1])
+	}
+}
+
+func TestProxy_IsStale(t *testing.T) {
+	proxy := &Proxy{LastCheckInTime: 1600000000000}
+	checkIn := time.Unix(1600000000, 0)
+	if !proxy.LastCheckIn().Equal(checkIn) {
+		t.Errorf("last check in expected %s, got %s", checkIn, proxy.LastCheckIn())
+	}
+
+	if proxy.IsStale(5*time.Minute, checkIn.Add(time.Minute)) {
+		t.Error("expected a proxy checked in a minute ago not to be stale")
+	}
+	if !proxy.IsStale(5*time.Minute, checkIn.Add(6*time.Minute)) {
+		t.Error("expected a proxy checked in 6 minutes ago to be stale")
+	}
+	if !(&Proxy{}).IsStale(time.Hour, time.Now()) {
+		t.Error("expected a proxy which never checked in to be stale")
+	}
+}
+
+func (m *MockCrudProxyClient) Do(req *http.Request) (io.ReadCloser, error) {
+	if req.Method != m.method {
+		m.T.Errorf("request method expected '%s' got '%s'", m.method, req.Method)
+	}
+	m.body = nil
+	if req.Body != nil {
+		m.body, _ = ioutil.ReadAll(req.Body)
+	}
+	response := `{"response":{"id":"1234","name":"renamed","hostname":"proxy-1"}}`
+	return ioutil.NopCloser(bytes.NewReader([]byte(response))), nil
+}
+
+func TestProxies_RenameDeleteProxy(t *testing.T) {
+	client := &MockCrudProxyClient{
+		Client: Client{
+			Config:     &Config{Token: "1234-5678-9977"},
+			BaseURL:    &url.URL{Scheme: "http", Host: "testing.wavefront.com"},
+			httpClient: http.DefaultClient,
+		},
+		method: "PUT",
+		T:      t,
+	}
+	p := &Proxies{client: client}
+
+	if err := p.Rename(&Proxy{}, "renamed"); err == nil {
+		t.Error("expected renaming a proxy with no ID to error")
+	}
+
+	id := "1234"
+	proxy := &Proxy{ID: &id, Name: "Proxy on proxy-1", LocalQueueSize: 5}
+	if err := p.Rename(proxy, "renamed"); err != nil {
+		t.Fatal(err)
+	}
+	if string(client.body) != `{"name":"renamed"}` {
+		t.Errorf("expected only the name to be sent, got %s", client.body)
+	}
+	if proxy.Name != "renamed" {
+		t.Errorf("expected the proxy to be renamed, got %s", proxy.Name)
+	}
+
+	client.method = "DELETE"
+	if err := p.Delete(proxy); err != nil {
+		t.Fatal(err)
+	}
+	if proxy.ID != nil {
+		t.Error("expected proxy ID to be reset after deletion")
+	}
+}
@@ -2,6 +2,7 @@ package wavefront
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -145,6 +146,11 @@ func (c *Client) NewQuery(params *QueryParams) *Query {
 
 // Execute is used to execute a query against the Wavefront Chart API
 func (q *Query) Execute() (*QueryResponse, error) {
+	return q.ExecuteContext(context.Background())
+}
+
+// ExecuteContext is like Execute but carries the given context through the request.
+func (q *Query) ExecuteContext(ctx context.Context) (*QueryResponse, error) {
 	queryResp := &QueryResponse{}
 
 	params := map[string]string{}
@@ -163,7 +169,7 @@ func (q *Query) Execute() (*QueryResponse, error) {
 		}
 	}
 
-	req, err := q.client.NewRequest("GET", baseQueryPath, &params, nil)
+	req, err := newRequestWithContext(ctx, q.client, "GET", baseQueryPath, &params, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,136 @@
+package wavefront
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateLimit configures client-side rate limiting of requests to the Wavefront
+// API. Queries against the chart API and all other (CRUD) requests are limited
+// by separate token buckets. A zero rate leaves that class of request unlimited.
+type RateLimit struct {
+	// QueryRate is the sustained number of requests per second allowed against
+	// the chart API (i.e. Query.Execute)
+	QueryRate float64
+
+	// QueryBurst is the number of chart API requests that may be made at once
+	// before QueryRate applies. Defaults to 1.
+	QueryBurst int
+
+	// CRUDRate is the sustained number of requests per second allowed against
+	// all other endpoints
+	CRUDRate float64
+
+	// CRUDBurst is the number of other requests that may be made at once
+	// before CRUDRate applies. Defaults to 1.
+	CRUDBurst int
+}
+
+// rateLimiter holds the token buckets shared by every service of a Client
+type rateLimiter struct {
+	query *tokenBucket
+	crud  *tokenBucket
+}
+
+func newRateLimiter(cfg *RateLimit) *rateLimiter {
+	if cfg == nil {
+		return nil
+	}
+	return &rateLimiter{
+		query: newTokenBucket(cfg.QueryRate, cfg.QueryBurst),
+		crud:  newTokenBucket(cfg.CRUDRate, cfg.CRUDBurst),
+	}
+}
+
+// wait blocks until req is allowed to be sent, or the request's context is done
+func (l *rateLimiter) wait(req *http.Request) error {
+	if l == nil {
+		return nil
+	}
+	b := l.crud
+	if strings.HasSuffix(strings.TrimSuffix(req.URL.Path, "/"), baseQueryPath) {
+		b = l.query
+	}
+	return b.wait(req.Context())
+}
+
+// tokenBucket is a simple token bucket rate limiter. A nil *tokenBucket
+// allows everything.
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+
+	// now is the clock used by the bucket, overridden in tests
+	now func() time.Time
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	if rate <= 0 {
+		return nil
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		now:    time.Now,
+	}
+}
+
+// reserve takes a token from the bucket and returns how long the caller must
+// wait before using it. The token count may go negative, which queues callers.
+func (b *tokenBucket) reserve() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	if !b.last.IsZero() {
+		b.tokens += now.Sub(b.last).Seconds() * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+	}
+	b.last = now
+
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// cancel returns a token taken by reserve that was never used
+func (b *tokenBucket) cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens++
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+}
+
+func (b *tokenBucket) wait(ctx context.Context) error {
+	if b == nil {
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	d := b.reserve()
+	if d == 0 {
+		return nil
+	}
+	if err := sleepContext(ctx, d); err != nil {
+		b.cancel()
+		return err
+	}
+	return nil
+}
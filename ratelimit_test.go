@@ -0,0 +1,71 @@
+package wavefront
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestTokenBucket(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := newTokenBucket(2, 2)
+	b.now = func() time.Time { return now }
+
+	// the burst is available immediately
+	for i := 0; i < 2; i++ {
+		if d := b.reserve(); d != 0 {
+			t.Errorf("burst request %d, expected no wait, got %s", i, d)
+		}
+	}
+
+	// then requests are spaced at the configured rate
+	if d := b.reserve(); d != 500*time.Millisecond {
+		t.Errorf("expected 500ms wait, got %s", d)
+	}
+	if d := b.reserve(); d != time.Second {
+		t.Errorf("expected 1s wait for queued request, got %s", d)
+	}
+
+	// tokens refill over time, up to the burst
+	now = now.Add(10 * time.Second)
+	if d := b.reserve(); d != 0 {
+		t.Errorf("after refill, expected no wait, got %s", d)
+	}
+	if b.tokens != 1 {
+		t.Errorf("tokens, expected refill capped at burst leaving 1, got %f", b.tokens)
+	}
+}
+
+func TestTokenBucket_WaitCancelled(t *testing.T) {
+	b := newTokenBucket(0.001, 1)
+	b.reserve()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := b.wait(ctx); err != context.DeadlineExceeded {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+	if b.tokens < -0.01 {
+		t.Errorf("expected cancelled wait to return its token, got %f tokens", b.tokens)
+	}
+}
+
+func TestRateLimiter_Buckets(t *testing.T) {
+	l := newRateLimiter(&RateLimit{QueryRate: 1})
+	if l.crud != nil {
+		t.Error("expected unlimited CRUD bucket when CRUDRate is zero")
+	}
+
+	query, _ := http.NewRequest("GET", "https://example.wavefront.com/api/v2/chart/api?q=ts(x)", nil)
+	crud, _ := http.NewRequest("GET", "https://example.wavefront.com/api/v2/alert/1234", nil)
+
+	l.wait(query)
+	if l.query.tokens != 0 {
+		t.Errorf("expected chart API request to consume a query token, got %f remaining", l.query.tokens)
+	}
+	if err := l.wait(crud); err != nil {
+		t.Errorf("expected CRUD request to be unlimited, got %v", err)
+	}
+}
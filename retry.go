@@ -0,0 +1,154 @@
+package wavefront
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultRetryInitialBackoff = 500 * time.Millisecond
+	defaultRetryMaxBackoff     = 30 * time.Second
+)
+
+// RetryPolicy configures how Client.Do retries failed requests.
+// Requests are retried on transport errors, 429 Too Many Requests and 5xx
+// responses. GET, PUT and DELETE requests are retried; POST requests are only
+// retried if RetryPOST is set.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts made for a request, including
+	// the first. A value of 1 or less disables retries.
+	MaxAttempts int
+
+	// InitialBackoff is the base delay before the first retry. The delay doubles
+	// with each subsequent attempt and has jitter applied. Defaults to 500ms.
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the delay between attempts, including any delay requested
+	// by a Retry-After header. Defaults to 30s.
+	MaxBackoff time.Duration
+
+	// RetryPOST allows POST requests to be retried. POST is not idempotent for
+	// most Wavefront endpoints, so this should only be set where duplicates are
+	// acceptable.
+	RetryPOST bool
+}
+
+// attempts returns the number of attempts allowed for the given method
+func (p *RetryPolicy) attempts(method string) int {
+	if p == nil || p.MaxAttempts < 1 {
+		return 1
+	}
+	switch method {
+	case "GET", "HEAD", "PUT", "DELETE", "OPTIONS":
+		return p.MaxAttempts
+	case "POST":
+		if p.RetryPOST {
+			return p.MaxAttempts
+		}
+	}
+	return 1
+}
+
+// retryable reports whether the outcome of a single attempt warrants a retry
+func (p *RetryPolicy) retryable(ctx context.Context, resp *http.Response, err error) bool {
+	if err != nil {
+		// neither a done context nor a request missing from a replayed
+		// cassette is a transient failure
+		return ctx.Err() == nil && !errors.Is(err, ErrCassetteMiss)
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
+
+// backoff returns the delay to wait after the given (1-based) attempt.
+// A Retry-After header on resp takes precedence over the computed delay.
+func (p *RetryPolicy) backoff(attempt int, resp *http.Response) time.Duration {
+	initial, max := p.InitialBackoff, p.MaxBackoff
+	if initial <= 0 {
+		initial = defaultRetryInitialBackoff
+	}
+	if max <= 0 {
+		max = defaultRetryMaxBackoff
+	}
+
+	if resp != nil {
+		if d, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			if d > max {
+				return max
+			}
+			return d
+		}
+	}
+
+	d := initial
+	for i := 1; i < attempt && d < max; i++ {
+		d *= 2
+	}
+	if d > max {
+		d = max
+	}
+	// "equal jitter": wait at least half the delay, plus a random remainder
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(d-half)+1))
+}
+
+// parseRetryAfter parses a Retry-After header value, which may be either a
+// number of seconds or an HTTP date.
+func parseRetryAfter(v string, now time.Time) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		d := t.Sub(now)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
+
+// rewindBody resets the body of req so that it can be sent again
+func rewindBody(req *http.Request) error {
+	if req.Body == nil || req.GetBody == nil {
+		return nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return err
+	}
+	req.Body = body
+	return nil
+}
+
+// discardBody drains and closes a response body so the connection can be reused
+func discardBody(resp *http.Response) {
+	if resp == nil {
+		return
+	}
+	io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+}
+
+// sleepContext waits for d, returning early with the context error if ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,137 @@
+package wavefront
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newRetryTestClient(t *testing.T, srv *httptest.Server, policy *RetryPolicy) *Client {
+	client, err := NewClient(&Config{
+		Address:       strings.TrimLeft(srv.URL, "https://"),
+		Token:         "123456789",
+		SkipTLSVerify: true,
+		Retry:         policy,
+	})
+	if err != nil {
+		t.Fatal("error initiating client:", err)
+	}
+	return client
+}
+
+func TestClientRetry(t *testing.T) {
+	body := []byte(`{ "some" : "json" }`)
+	calls := 0
+
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		actualBody, _ := ioutil.ReadAll(r.Body)
+		if string(actualBody) != string(body) {
+			t.Errorf("request body on attempt %d, expected %s got %s", calls, string(body), string(actualBody))
+		}
+		if calls < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	client := newRetryTestClient(t, srv, &RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+	})
+
+	req, err := client.NewRequest("PUT", "test/thing", nil, body)
+	if err != nil {
+		t.Fatal("error creating request:", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal("error executing request:", err)
+	}
+	resp.Close()
+
+	if calls != 3 {
+		t.Errorf("attempts, expected 3, got %d", calls)
+	}
+}
+
+func TestClientRetryPOST(t *testing.T) {
+	calls := 0
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	policy := &RetryPolicy{
+		MaxAttempts:    2,
+		InitialBackoff: time.Millisecond,
+	}
+	client := newRetryTestClient(t, srv, policy)
+
+	req, _ := client.NewRequest("POST", "test/thing", nil, []byte(`{}`))
+	if _, err := client.Do(req); err == nil {
+		t.Error("expected error from 429 response")
+	}
+	if calls != 1 {
+		t.Errorf("POST attempts without RetryPOST, expected 1, got %d", calls)
+	}
+
+	calls = 0
+	policy.RetryPOST = true
+	req, _ = client.NewRequest("POST", "test/thing", nil, []byte(`{}`))
+	client.Do(req)
+	if calls != 2 {
+		t.Errorf("POST attempts with RetryPOST, expected 2, got %d", calls)
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	p := &RetryPolicy{
+		MaxAttempts:    5,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     time.Second,
+	}
+
+	for attempt := 1; attempt <= 5; attempt++ {
+		d := p.backoff(attempt, nil)
+		if d > time.Second {
+			t.Errorf("backoff on attempt %d exceeds max: %s", attempt, d)
+		}
+		if d < 50*time.Millisecond {
+			t.Errorf("backoff on attempt %d below half the initial delay: %s", attempt, d)
+		}
+	}
+
+	resp := &http.Response{Header: http.Header{}}
+	resp.Header.Set("Retry-After", "0")
+	if d := p.backoff(1, resp); d != 0 {
+		t.Errorf("Retry-After 0, expected no delay, got %s", d)
+	}
+	resp.Header.Set("Retry-After", "120")
+	if d := p.backoff(1, resp); d != time.Second {
+		t.Errorf("Retry-After 120, expected delay capped at 1s, got %s", d)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	if d, ok := parseRetryAfter("5", now); !ok || d != 5*time.Second {
+		t.Errorf("seconds, expected 5s, got %s (%v)", d, ok)
+	}
+
+	date := now.Add(10 * time.Second).Format(http.TimeFormat)
+	if d, ok := parseRetryAfter(date, now); !ok || d != 10*time.Second {
+		t.Errorf("http date, expected 10s, got %s (%v)", d, ok)
+	}
+
+	if _, ok := parseRetryAfter("soon", now); ok {
+		t.Error("expected invalid Retry-After to be rejected")
+	}
+}
@@ -0,0 +1,244 @@
+package wavefront
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+)
+
+// Role represents a single Wavefront Role
+type Role struct {
+	// ID is the Wavefront-assigned ID of an existing Role
+	ID *string `json:"id,omitempty"`
+
+	// Name is the name given to the Role
+	Name string `json:"name"`
+
+	// Description is a description of the Role
+	Description string `json:"description,omitempty"`
+
+	// Permissions are the permissions granted by the Role,
+	// e.g. alerts_management
+	Permissions []string `json:"permissions"`
+
+	LinkedGroupsCount   int    `json:"linkedGroupsCount,omitempty"`
+	LinkedAccountsCount int    `json:"linkedAccountsCount,omitempty"`
+	Customer            string `json:"customer,omitempty"`
+	CreatedEpochMillis  int64  `json:"createdEpochMillis,omitempty"`
+	LastUpdatedMs       int64  `json:"lastUpdatedMs,omitempty"`
+	LastUpdatedAccount  string `json:"lastUpdatedAccountId,omitempty"`
+}
+
+// Roles is used to perform role-related operations against the Wavefront API
+type Roles struct {
+	// client is the Wavefront client used to perform role-related operations
+	client Wavefronter
+}
+
+const baseRolePath = "/api/v2/role"
+
+// MarshalJSON is a custom JSON marshaller for a Role. Wavefront accepts a
+// different shape of role than it returns, so only the fields that can be set
+// are sent.
+func (r *Role) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&struct {
+		ID          *string  `json:"id,omitempty"`
+		Name        string   `json:"name"`
+		Description string   `json:"description,omitempty"`
+		Permissions []string `json:"permissions"`
+	}{
+		ID:          r.ID,
+		Name:        r.Name,
+		Description: r.Description,
+		Permissions: r.Permissions,
+	})
+}
+
+// Roles is used to return a client for role-related operations
+func (c *Client) Roles() *Roles {
+	return &Roles{client: c}
+}
+
+// Get is used to retrieve an existing Role by ID.
+// The ID field must be provided
+func (r Roles) Get(role *Role) error {
+	return r.GetContext(context.Background(), role)
+}
+
+// GetContext is like Get but carries the given context through the request.
+func (r Roles) GetContext(ctx context.Context, role *Role) error {
+	if role.ID == nil || *role.ID == "" {
+		return fmt.Errorf("Role id field is not set")
+	}
+
+	return r.crudRole(ctx, "GET", fmt.Sprintf("%s/%s", baseRolePath, *role.ID), role)
+}
+
+// Find returns all roles filtered by the given search conditions.
+// If filter is nil, all roles are returned.
+func (r Roles) Find(filter []*SearchCondition) ([]*Role, error) {
+	return r.FindContext(context.Background(), filter)
+}
+
+// FindContext is like Find but carries the given context through every page
+// of the search. Pagination stops as soon as the context is done.
+func (r Roles) FindContext(ctx context.Context, filter []*SearchCondition) ([]*Role, error) {
+	search := &Search{
+		client: r.client,
+		Type:   "role",
+		Params: &SearchParams{
+			Conditions: filter,
+		},
+	}
+
+	var results []*Role
+	moreItems := true
+	for moreItems == true {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		resp, err := search.ExecuteContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		var tmpres []*Role
+		err = json.Unmarshal(resp.Response.Items, &tmpres)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, tmpres...)
+		moreItems = resp.Response.MoreItems
+		search.Params.Offset = resp.NextOffset
+	}
+
+	return results, nil
+}
+
+// Create is used to create a Role in Wavefront.
+// If successful, the ID field of the role will be populated.
+func (r Roles) Create(role *Role) error {
+	return r.CreateContext(context.Background(), role)
+}
+
+// CreateContext is like Create but carries the given context through the request.
+func (r Roles) CreateContext(ctx context.Context, role *Role) error {
+	return r.crudRole(ctx, "POST", baseRolePath, role)
+}
+
+// Update is used to update an existing Role.
+// The ID field of the role must be populated
+func (r Roles) Update(role *Role) error {
+	return r.UpdateContext(context.Background(), role)
+}
+
+// UpdateContext is like Update but carries the given context through the request.
+func (r Roles) UpdateContext(ctx context.Context, role *Role) error {
+	if role.ID == nil {
+		return fmt.Errorf("role id field not set")
+	}
+
+	return r.crudRole(ctx, "PUT", fmt.Sprintf("%s/%s", baseRolePath, *role.ID), role)
+}
+
+// Delete is used to delete an existing Role.
+// The ID field of the role must be populated
+func (r Roles) Delete(role *Role) error {
+	return r.DeleteContext(context.Background(), role)
+}
+
+// DeleteContext is like Delete but carries the given context through the request.
+func (r Roles) DeleteContext(ctx context.Context, role *Role) error {
+	if role.ID == nil {
+		return fmt.Errorf("role id field not set")
+	}
+
+	err := r.crudRole(ctx, "DELETE", fmt.Sprintf("%s/%s", baseRolePath, *role.ID), role)
+	if err != nil {
+		return err
+	}
+
+	//reset the ID field so deletion is not attempted again
+	role.ID = nil
+	return nil
+}
+
+// GrantPermission is used to grant a permission, e.g. alerts_management, to
+// the roles with the given IDs
+func (r Roles) GrantPermission(permission string, roleIDs ...string) error {
+	return r.GrantPermissionContext(context.Background(), permission, roleIDs...)
+}
+
+// GrantPermissionContext is like GrantPermission but carries the given context
+// through the request.
+func (r Roles) GrantPermissionContext(ctx context.Context, permission string, roleIDs ...string) error {
+	return r.permissionAction(ctx, "grant", permission, roleIDs)
+}
+
+// RevokePermission is used to revoke a permission, e.g. alerts_management,
+// from the roles with the given IDs
+func (r Roles) RevokePermission(permission string, roleIDs ...string) error {
+	return r.RevokePermissionContext(context.Background(), permission, roleIDs...)
+}
+
+// RevokePermissionContext is like RevokePermission but carries the given
+// context through the request.
+func (r Roles) RevokePermissionContext(ctx context.Context, permission string, roleIDs ...string) error {
+	return r.permissionAction(ctx, "revoke", permission, roleIDs)
+}
+
+// permissionAction POSTs the role IDs to the grant or revoke endpoint of a
+// permission
+func (r Roles) permissionAction(ctx context.Context, action, permission string, roleIDs []string) error {
+	if permission == "" {
+		return fmt.Errorf("permission must be provided")
+	}
+	if len(roleIDs) == 0 {
+		return fmt.Errorf("at least one role id must be provided")
+	}
+
+	payload, err := json.Marshal(roleIDs)
+	if err != nil {
+		return err
+	}
+	req, err := newRequestWithContext(ctx, r.client, "POST",
+		fmt.Sprintf("%s/%s/%s", baseRolePath, action, url.PathEscape(permission)), nil, payload)
+	if err != nil {
+		return err
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	return resp.Close()
+}
+
+func (r Roles) crudRole(ctx context.Context, method, path string, role *Role) error {
+	payload, err := json.Marshal(role)
+	if err != nil {
+		return err
+	}
+	req, err := newRequestWithContext(ctx, r.client, method, path, nil, payload)
+	if err != nil {
+		return err
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Close()
+
+	body, err := ioutil.ReadAll(resp)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(body, &struct {
+		Response *Role `json:"response"`
+	}{
+		Response: role,
+	})
+}
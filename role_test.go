@@ -0,0 +1,118 @@
+package wavefront
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+type MockCrudRoleClient struct {
+	Client
+	method string
+	path   string
+	body   []byte
+	T      *testing.T
+}
+
+func (m *MockCrudRoleClient) Do(req *http.Request) (io.ReadCloser, error) {
+	response, err := ioutil.ReadFile("./fixtures/create-role-response.json")
+	if err != nil {
+		m.T.Fatal(err)
+	}
+	if req.Method != m.method {
+		m.T.Errorf("request method expected '%s' got '%s'", m.method, req.Method)
+	}
+	m.path = req.URL.Path
+	m.body, _ = ioutil.ReadAll(req.Body)
+	return ioutil.NopCloser(bytes.NewReader(response)), nil
+}
+
+func TestRoles_CreateUpdateDeleteRole(t *testing.T) {
+	baseurl, _ := url.Parse("http://testing.wavefront.com")
+	client := &MockCrudRoleClient{
+		Client: Client{
+			Config:     &Config{Token: "1234-5678-9977"},
+			BaseURL:    baseurl,
+			httpClient: http.DefaultClient,
+			debug:      true,
+		},
+		method: "PUT",
+		T:      t,
+	}
+	r := &Roles{client: client}
+
+	role := Role{
+		Name:        "Operators",
+		Description: "Manage alerts and events",
+		Permissions: []string{"alerts_management", "events_management"},
+	}
+
+	if err := r.Update(&role); err == nil {
+		t.Errorf("expected role update to error with no ID")
+	}
+
+	client.method = "POST"
+	if err := r.Create(&role); err != nil {
+		t.Fatal(err)
+	}
+	if *role.ID != "role-1" {
+		t.Errorf("role ID expected role-1, got %s", *role.ID)
+	}
+	if role.LinkedGroupsCount != 1 {
+		t.Errorf("linked groups expected 1, got %d", role.LinkedGroupsCount)
+	}
+
+	client.method = "PUT"
+	if err := r.Update(&role); err != nil {
+		t.Error(err)
+	}
+	if bytes.Contains(client.body, []byte("linkedGroupsCount")) {
+		t.Errorf("expected read-only fields not to be sent, got %s", client.body)
+	}
+
+	client.method = "DELETE"
+	if err := r.Delete(&role); err != nil {
+		t.Error(err)
+	}
+
+	if role.ID != nil {
+		t.Error("expected role ID to be reset after deletion")
+	}
+}
+
+func TestRoles_GrantRevokePermission(t *testing.T) {
+	client := &MockCrudRoleClient{
+		Client: Client{
+			Config:     &Config{Token: "1234-5678-9977"},
+			BaseURL:    &url.URL{Scheme: "http", Host: "testing.wavefront.com"},
+			httpClient: http.DefaultClient,
+		},
+		method: "POST",
+		T:      t,
+	}
+	r := &Roles{client: client}
+
+	if err := r.GrantPermission("alerts_management"); err == nil {
+		t.Error("expected granting a permission to no roles to error")
+	}
+
+	if err := r.GrantPermission("alerts_management", "role-1", "role-2"); err != nil {
+		t.Fatal(err)
+	}
+	if client.path != "/api/v2/role/grant/alerts_management" {
+		t.Errorf("unexpected path %s", client.path)
+	}
+	if string(client.body) != `["role-1","role-2"]` {
+		t.Errorf("unexpected body %s", client.body)
+	}
+
+	if err := r.RevokePermission("alerts_management", "role-1"); err != nil {
+		t.Fatal(err)
+	}
+	if client.path != "/api/v2/role/revoke/alerts_management" {
+		t.Errorf("unexpected path %s", client.path)
+	}
+}
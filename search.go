@@ -2,6 +2,7 @@ package wavefront
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -125,6 +126,11 @@ func NewTimeRange(endTime, period int64) (*TimeRange, error) {
 
 // Execute is used to carry out a search
 func (s *Search) Execute() (*SearchResponse, error) {
+	return s.ExecuteContext(context.Background())
+}
+
+// ExecuteContext is like Execute but carries the given context through the request.
+func (s *Search) ExecuteContext(ctx context.Context) (*SearchResponse, error) {
 	// set defaults
 	if s.Params.Limit == 0 {
 		s.Params.Limit = 100
@@ -139,7 +145,7 @@ func (s *Search) Execute() (*SearchResponse, error) {
 	if s.Deleted == true {
 		path += "/deleted"
 	}
-	req, err := s.client.NewRequest("POST", path, nil, payload)
+	req, err := newRequestWithContext(ctx, s.client, "POST", path, nil, payload)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,303 @@
+package wavefront
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+)
+
+// ServiceAccount represents a single Wavefront Service Account, an account
+// used by tools rather than people, which authenticates with API tokens
+type ServiceAccount struct {
+	// ID is the identifier of the Service Account, which must begin with "sa::"
+	ID *string `json:"identifier,omitempty"`
+
+	// Description is a description of the Service Account
+	Description string `json:"description,omitempty"`
+
+	// Active is true if the Service Account may be used. It is changed with
+	// Activate and Deactivate.
+	Active bool `json:"active"`
+
+	// Permissions are the permissions granted directly to the Service Account,
+	// e.g. alerts_management
+	Permissions []string `json:"groups"`
+
+	// UserGroups are the IDs of the user groups the Service Account belongs to
+	UserGroups []string `json:"-"`
+
+	// Roles are the IDs of the roles assigned directly to the Service Account
+	Roles []string `json:"-"`
+
+	// Tokens are the API tokens of the Service Account. They are managed with
+	// APITokens.
+	Tokens []*APIToken `json:"tokens,omitempty"`
+
+	LastUsed int64 `json:"lastUsed,omitempty"`
+}
+
+// ServiceAccounts is used to perform service account-related operations
+// against the Wavefront API
+type ServiceAccounts struct {
+	// client is the Wavefront client used to perform service account-related operations
+	client Wavefronter
+}
+
+const (
+	baseServiceAccountPath = "/api/v2/account/serviceaccount"
+
+	// baseAccountPath is used to delete accounts, which is common to users
+	// and service accounts
+	baseAccountPath = "/api/v2/account"
+)
+
+// UnmarshalJSON is a custom JSON unmarshaller for a ServiceAccount, used in
+// order to reduce the user groups and roles Wavefront returns to their IDs
+func (s *ServiceAccount) UnmarshalJSON(b []byte) error {
+	type serviceAccount ServiceAccount
+	temp := struct {
+		UserGroups json.RawMessage `json:"userGroups"`
+		Roles      json.RawMessage `json:"roles"`
+		*serviceAccount
+	}{
+		serviceAccount: (*serviceAccount)(s),
+	}
+	if err := json.Unmarshal(b, &temp); err != nil {
+		return err
+	}
+
+	var err error
+	if s.UserGroups, err = idList(temp.UserGroups); err != nil {
+		return err
+	}
+	s.Roles, err = idList(temp.Roles)
+	return err
+}
+
+// MarshalJSON is a custom JSON marshaller for a ServiceAccount. Wavefront
+// accepts a different shape of service account than it returns, so only the
+// fields that can be set are sent, with the tokens reduced to their IDs.
+func (s *ServiceAccount) MarshalJSON() ([]byte, error) {
+	var tokens []string
+	for _, t := range s.Tokens {
+		if t.ID != nil {
+			tokens = append(tokens, *t.ID)
+		}
+	}
+	return json.Marshal(&struct {
+		ID          *string  `json:"identifier,omitempty"`
+		Description string   `json:"description,omitempty"`
+		Active      bool     `json:"active"`
+		Permissions []string `json:"groups"`
+		UserGroups  []string `json:"userGroups"`
+		Roles       []string `json:"roles"`
+		Tokens      []string `json:"tokens,omitempty"`
+	}{
+		ID:          s.ID,
+		Description: s.Description,
+		Active:      s.Active,
+		Permissions: s.Permissions,
+		UserGroups:  s.UserGroups,
+		Roles:       s.Roles,
+		Tokens:      tokens,
+	})
+}
+
+// ServiceAccounts is used to return a client for service account-related operations
+func (c *Client) ServiceAccounts() *ServiceAccounts {
+	return &ServiceAccounts{client: c}
+}
+
+// Get is used to retrieve an existing ServiceAccount by ID.
+// The ID field must be provided
+func (s ServiceAccounts) Get(account *ServiceAccount) error {
+	return s.GetContext(context.Background(), account)
+}
+
+// GetContext is like Get but carries the given context through the request.
+func (s ServiceAccounts) GetContext(ctx context.Context, account *ServiceAccount) error {
+	if account.ID == nil || *account.ID == "" {
+		return fmt.Errorf("ServiceAccount id field is not set")
+	}
+
+	return s.crudServiceAccount(ctx, "GET", fmt.Sprintf("%s/%s", baseServiceAccountPath, *account.ID), account)
+}
+
+// Find returns all service accounts filtered by the given search conditions.
+// If filter is nil, all service accounts are returned.
+func (s ServiceAccounts) Find(filter []*SearchCondition) ([]*ServiceAccount, error) {
+	return s.FindContext(context.Background(), filter)
+}
+
+// FindContext is like Find but carries the given context through every page
+// of the search. Pagination stops as soon as the context is done.
+func (s ServiceAccounts) FindContext(ctx context.Context, filter []*SearchCondition) ([]*ServiceAccount, error) {
+	search := &Search{
+		client: s.client,
+		Type:   "serviceaccount",
+		Params: &SearchParams{
+			Conditions: filter,
+		},
+	}
+
+	var results []*ServiceAccount
+	moreItems := true
+	for moreItems == true {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		resp, err := search.ExecuteContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		var tmpres []*ServiceAccount
+		err = json.Unmarshal(resp.Response.Items, &tmpres)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, tmpres...)
+		moreItems = resp.Response.MoreItems
+		search.Params.Offset = resp.NextOffset
+	}
+
+	return results, nil
+}
+
+// Create is used to create a ServiceAccount in Wavefront.
+// The ID field must be set, and must begin with "sa::"
+func (s ServiceAccounts) Create(account *ServiceAccount) error {
+	return s.CreateContext(context.Background(), account)
+}
+
+// CreateContext is like Create but carries the given context through the request.
+func (s ServiceAccounts) CreateContext(ctx context.Context, account *ServiceAccount) error {
+	if account.ID == nil || *account.ID == "" {
+		return fmt.Errorf("ServiceAccount id field is not set")
+	}
+
+	return s.crudServiceAccount(ctx, "POST", baseServiceAccountPath, account)
+}
+
+// Update is used to update an existing ServiceAccount.
+// The ID field of the service account must be populated
+func (s ServiceAccounts) Update(account *ServiceAccount) error {
+	return s.UpdateContext(context.Background(), account)
+}
+
+// UpdateContext is like Update but carries the given context through the request.
+func (s ServiceAccounts) UpdateContext(ctx context.Context, account *ServiceAccount) error {
+	if account.ID == nil {
+		return fmt.Errorf("service account id field not set")
+	}
+
+	return s.crudServiceAccount(ctx, "PUT", fmt.Sprintf("%s/%s", baseServiceAccountPath, *account.ID), account)
+}
+
+// Activate is used to activate an existing ServiceAccount, which is updated
+// from the response. The ID field of the service account must be populated
+func (s ServiceAccounts) Activate(account *ServiceAccount) error {
+	return s.ActivateContext(context.Background(), account)
+}
+
+// ActivateContext is like Activate but carries the given context through the request.
+func (s ServiceAccounts) ActivateContext(ctx context.Context, account *ServiceAccount) error {
+	return s.serviceAccountAction(ctx, "activate", account)
+}
+
+// Deactivate is used to deactivate an existing ServiceAccount, so that its
+// tokens are no longer accepted. The service account is updated from the
+// response. The ID field of the service account must be populated
+func (s ServiceAccounts) Deactivate(account *ServiceAccount) error {
+	return s.DeactivateContext(context.Background(), account)
+}
+
+// DeactivateContext is like Deactivate but carries the given context through the request.
+func (s ServiceAccounts) DeactivateContext(ctx context.Context, account *ServiceAccount) error {
+	return s.serviceAccountAction(ctx, "deactivate", account)
+}
+
+// Delete is used to delete an existing ServiceAccount, along with its tokens.
+// The ID field of the service account must be populated
+func (s ServiceAccounts) Delete(account *ServiceAccount) error {
+	return s.DeleteContext(context.Background(), account)
+}
+
+// DeleteContext is like Delete but carries the given context through the request.
+func (s ServiceAccounts) DeleteContext(ctx context.Context, account *ServiceAccount) error {
+	if account.ID == nil {
+		return fmt.Errorf("service account id field not set")
+	}
+
+	err := s.crudServiceAccount(ctx, "DELETE", fmt.Sprintf("%s/%s", baseAccountPath, *account.ID), account)
+	if err != nil {
+		return err
+	}
+
+	//reset the ID field so deletion is not attempted again
+	account.ID = nil
+	return nil
+}
+
+// serviceAccountAction POSTs to an action endpoint of a ServiceAccount, such
+// as activate, and replaces account with the ServiceAccount in the response
+func (s ServiceAccounts) serviceAccountAction(ctx context.Context, action string, account *ServiceAccount) error {
+	if account.ID == nil {
+		return fmt.Errorf("service account id field not set")
+	}
+
+	req, err := newRequestWithContext(ctx, s.client, "POST", fmt.Sprintf("%s/%s/%s", baseServiceAccountPath, *account.ID, action), nil, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Close()
+
+	body, err := ioutil.ReadAll(resp)
+	if err != nil {
+		return err
+	}
+
+	temp := struct {
+		Response *ServiceAccount `json:"response"`
+	}{}
+	if err := json.Unmarshal(body, &temp); err != nil {
+		return err
+	}
+	if temp.Response != nil {
+		*account = *temp.Response
+	}
+	return nil
+}
+
+func (s ServiceAccounts) crudServiceAccount(ctx context.Context, method, path string, account *ServiceAccount) error {
+	payload, err := json.Marshal(account)
+	if err != nil {
+		return err
+	}
+	req, err := newRequestWithContext(ctx, s.client, method, path, nil, payload)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Close()
+
+	body, err := ioutil.ReadAll(resp)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(body, &struct {
+		Response *ServiceAccount `json:"response"`
+	}{
+		Response: account,
+	})
+}
@@ -0,0 +1,102 @@
+package wavefront
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+type MockCrudServiceAccountClient struct {
+	Client
+	method string
+	path   string
+	body   []byte
+	T      *testing.T
+}
+
+func (m *MockCrudServiceAccountClient) Do(req *http.Request) (io.ReadCloser, error) {
+	response, err := ioutil.ReadFile("./fixtures/create-serviceaccount-response.json")
+	if err != nil {
+		m.T.Fatal(err)
+	}
+	if req.Method != m.method {
+		m.T.Errorf("request method expected '%s' got '%s'", m.method, req.Method)
+	}
+	m.path = req.URL.Path
+	if req.Body != nil {
+		m.body, _ = ioutil.ReadAll(req.Body)
+	}
+	return ioutil.NopCloser(bytes.NewReader(response)), nil
+}
+
+func TestServiceAccounts_CreateUpdateDeleteServiceAccount(t *testing.T) {
+	baseurl, _ := url.Parse("http://testing.wavefront.com")
+	client := &MockCrudServiceAccountClient{
+		Client: Client{
+			Config:     &Config{Token: "1234-5678-9977"},
+			BaseURL:    baseurl,
+			httpClient: http.DefaultClient,
+			debug:      true,
+		},
+		method: "POST",
+		T:      t,
+	}
+	s := &ServiceAccounts{client: client}
+
+	if err := s.Create(&ServiceAccount{}); err == nil {
+		t.Errorf("expected service account create to error with no ID")
+	}
+
+	id := "sa::ci"
+	account := ServiceAccount{
+		ID:          &id,
+		Description: "Continuous integration",
+		Active:      true,
+		Permissions: []string{"events_management"},
+	}
+	if err := s.Create(&account); err != nil {
+		t.Fatal(err)
+	}
+	if len(account.Tokens) != 1 || account.Tokens[0].Name != "deploys" {
+		t.Errorf("expected a token named deploys, got %+v", account.Tokens)
+	}
+	if len(account.UserGroups) != 1 || account.UserGroups[0] != "group-1" {
+		t.Errorf("user groups expected [group-1], got %v", account.UserGroups)
+	}
+
+	client.method = "PUT"
+	if err := s.Update(&account); err != nil {
+		t.Fatal(err)
+	}
+	sent := map[string]interface{}{}
+	if err := json.Unmarshal(client.body, &sent); err != nil {
+		t.Fatal(err)
+	}
+	if tokens, ok := sent["tokens"].([]interface{}); !ok || len(tokens) != 1 || tokens[0] != *account.Tokens[0].ID {
+		t.Errorf("expected the token IDs to be sent, got %s", client.body)
+	}
+
+	client.method = "POST"
+	if err := s.Deactivate(&account); err != nil {
+		t.Fatal(err)
+	}
+	if client.path != "/api/v2/account/serviceaccount/sa::ci/deactivate" {
+		t.Errorf("unexpected path %s", client.path)
+	}
+
+	client.method = "DELETE"
+	if err := s.Delete(&account); err != nil {
+		t.Error(err)
+	}
+	if client.path != "/api/v2/account/sa::ci" {
+		t.Errorf("unexpected path %s", client.path)
+	}
+
+	if account.ID != nil {
+		t.Error("expected service account ID to be reset after deletion")
+	}
+}
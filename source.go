@@ -0,0 +1,361 @@
+package wavefront
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"sort"
+	"strconv"
+)
+
+// MetricSource represents a single Wavefront Source, typically a host, which
+// metrics are reported from. It is not to be confused with the Source of a
+// Chart, which is a query.
+type MetricSource struct {
+	// ID is the name of the MetricSource, e.g. its hostname
+	ID *string `json:"id,omitempty"`
+
+	// Description is a description of the MetricSource. It is changed with
+	// SetDescription and RemoveDescription.
+	Description string `json:"description,omitempty"`
+
+	// Tags are the source tags of the MetricSource, which charts and alerts can
+	// filter on. They are changed with AddTag, RemoveTag and SetTags.
+	Tags []string `json:"-"`
+
+	// Hidden is true if the MetricSource is hidden from the UI
+	Hidden bool `json:"hidden,omitempty"`
+
+	MarkedNewEpochMillis int64  `json:"markedNewEpochMillis,omitempty"`
+	CreatorId            string `json:"creatorId,omitempty"`
+	UpdaterId            string `json:"updaterId,omitempty"`
+	CreatedEpochMillis   int64  `json:"createdEpochMillis,omitempty"`
+	UpdatedEpochMillis   int64  `json:"updatedEpochMillis,omitempty"`
+}
+
+// Sources is used to perform source-related operations against the Wavefront API
+type Sources struct {
+	// client is the Wavefront client used to perform source-related operations
+	client Wavefronter
+}
+
+const baseSourcePath = "/api/v2/source"
+
+// sourcePageSize is the number of sources requested at a time by List
+const sourcePageSize = 100
+
+// UnmarshalJSON is a custom JSON unmarshaller for a MetricSource, used in
+// order to populate the Tags field from the map of tags Wavefront returns
+func (s *MetricSource) UnmarshalJSON(b []byte) error {
+	type metricSource MetricSource
+	temp := struct {
+		Tags map[string]bool `json:"tags"`
+		*metricSource
+	}{
+		metricSource: (*metricSource)(s),
+	}
+	if err := json.Unmarshal(b, &temp); err != nil {
+		return err
+	}
+
+	s.Tags = nil
+	for tag, set := range temp.Tags {
+		if set {
+			s.Tags = append(s.Tags, tag)
+		}
+	}
+	sort.Strings(s.Tags)
+	return nil
+}
+
+func (s *MetricSource) MarshalJSON() ([]byte, error) {
+	type metricSource MetricSource
+	tags := map[string]bool{}
+	for _, tag := range s.Tags {
+		tags[tag] = true
+	}
+	return json.Marshal(&struct {
+		Tags map[string]bool `json:"tags"`
+		*metricSource
+	}{
+		Tags:         tags,
+		metricSource: (*metricSource)(s),
+	})
+}
+
+// Sources is used to return a client for source-related operations
+func (c *Client) Sources() *Sources {
+	return &Sources{client: c}
+}
+
+// Get is used to retrieve an existing MetricSource by ID.
+// The ID field must be provided
+func (s Sources) Get(source *MetricSource) error {
+	return s.GetContext(context.Background(), source)
+}
+
+// GetContext is like Get but carries the given context through the request.
+func (s Sources) GetContext(ctx context.Context, source *MetricSource) error {
+	if source.ID == nil || *source.ID == "" {
+		return fmt.Errorf("MetricSource id field is not set")
+	}
+
+	req, err := newRequestWithContext(ctx, s.client, "GET", sourcePath(*source.ID), nil, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Close()
+
+	body, err := ioutil.ReadAll(resp)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(body, &struct {
+		Response *MetricSource `json:"response"`
+	}{
+		Response: source,
+	})
+}
+
+// ListPage returns a page of at most limit sources, starting from the given
+// cursor, along with the cursor of the next page. The first page is returned
+// for an empty cursor, and the next cursor is empty after the last page.
+func (s Sources) ListPage(cursor string, limit int) ([]*MetricSource, string, error) {
+	return s.ListPageContext(context.Background(), cursor, limit)
+}
+
+// ListPageContext is like ListPage but carries the given context through the request.
+func (s Sources) ListPageContext(ctx context.Context, cursor string, limit int) ([]*MetricSource, string, error) {
+	params := map[string]string{
+		"limit": strconv.Itoa(limit),
+	}
+	if cursor != "" {
+		params["cursor"] = cursor
+	}
+	req, err := newRequestWithContext(ctx, s.client, "GET", baseSourcePath, &params, nil)
+	if err != nil {
+		return nil, "", err
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Close()
+
+	body, err := ioutil.ReadAll(resp)
+	if err != nil {
+		return nil, "", err
+	}
+
+	page := struct {
+		Response struct {
+			Items     []*MetricSource `json:"items"`
+			Cursor    string          `json:"cursor"`
+			MoreItems bool            `json:"moreItems"`
+		} `json:"response"`
+	}{}
+	if err := json.Unmarshal(body, &page); err != nil {
+		return nil, "", err
+	}
+	if !page.Response.MoreItems {
+		return page.Response.Items, "", nil
+	}
+	return page.Response.Items, page.Response.Cursor, nil
+}
+
+// List returns every MetricSource, following the cursor through every page
+func (s Sources) List() ([]*MetricSource, error) {
+	return s.ListContext(context.Background())
+}
+
+// ListContext is like List but carries the given context through every page
+// of the listing. Pagination stops as soon as the context is done.
+func (s Sources) ListContext(ctx context.Context) ([]*MetricSource, error) {
+	var results []*MetricSource
+	cursor := ""
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		sources, next, err := s.ListPageContext(ctx, cursor, sourcePageSize)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, sources...)
+		if next == "" || next == cursor {
+			return results, nil
+		}
+		cursor = next
+	}
+}
+
+// FindByTag returns every MetricSource with the given source tag
+func (s Sources) FindByTag(tag string) ([]*MetricSource, error) {
+	return s.FindByTagContext(context.Background(), tag)
+}
+
+// FindByTagContext is like FindByTag but carries the given context through
+// every page of the search. Pagination stops as soon as the context is done.
+func (s Sources) FindByTagContext(ctx context.Context, tag string) ([]*MetricSource, error) {
+	search := &Search{
+		client: s.client,
+		Type:   "source",
+		Params: &SearchParams{
+			Conditions: []*SearchCondition{
+				{Key: "tags", Value: tag, MatchingMethod: "EXACT"},
+			},
+		},
+	}
+
+	var results []*MetricSource
+	moreItems := true
+	for moreItems {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		resp, err := search.ExecuteContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		var sources []*MetricSource
+		if err := json.Unmarshal(resp.Response.Items, &sources); err != nil {
+			return nil, err
+		}
+		results = append(results, sources...)
+		moreItems = resp.Response.MoreItems
+		search.Params.Offset = resp.NextOffset
+	}
+
+	return results, nil
+}
+
+// AddTag adds a source tag to an existing MetricSource, and adds it to the
+// Tags of source too. The ID field of the source must be populated
+func (s Sources) AddTag(source *MetricSource, tag string) error {
+	return s.AddTagContext(context.Background(), source, tag)
+}
+
+// AddTagContext is like AddTag but carries the given context through the request.
+func (s Sources) AddTagContext(ctx context.Context, source *MetricSource, tag string) error {
+	if source.ID == nil {
+		return fmt.Errorf("source id field not set")
+	}
+
+	if err := addTag(ctx, s.client, sourcePath(*source.ID), tag); err != nil {
+		return err
+	}
+	source.Tags = withTag(source.Tags, tag)
+	return nil
+}
+
+// RemoveTag removes a source tag from an existing MetricSource, and removes it
+// from the Tags of source too. The ID field of the source must be populated
+func (s Sources) RemoveTag(source *MetricSource, tag string) error {
+	return s.RemoveTagContext(context.Background(), source, tag)
+}
+
+// RemoveTagContext is like RemoveTag but carries the given context through the request.
+func (s Sources) RemoveTagContext(ctx context.Context, source *MetricSource, tag string) error {
+	if source.ID == nil {
+		return fmt.Errorf("source id field not set")
+	}
+
+	if err := removeTag(ctx, s.client, sourcePath(*source.ID), tag); err != nil {
+		return err
+	}
+	source.Tags = withoutTag(source.Tags, tag)
+	return nil
+}
+
+// SetTags replaces the source tags of an existing MetricSource, and sets the
+// Tags of source too. No tags removes every tag.
+// The ID field of the source must be populated
+func (s Sources) SetTags(source *MetricSource, tags ...string) error {
+	return s.SetTagsContext(context.Background(), source, tags...)
+}
+
+// SetTagsContext is like SetTags but carries the given context through the request.
+func (s Sources) SetTagsContext(ctx context.Context, source *MetricSource, tags ...string) error {
+	if source.ID == nil {
+		return fmt.Errorf("source id field not set")
+	}
+
+	if err := setTags(ctx, s.client, sourcePath(*source.ID), tags); err != nil {
+		return err
+	}
+	source.Tags = tags
+	return nil
+}
+
+// SetDescription sets the description of an existing MetricSource, and the
+// Description of source too. The ID field of the source must be populated
+func (s Sources) SetDescription(source *MetricSource, description string) error {
+	return s.SetDescriptionContext(context.Background(), source, description)
+}
+
+// SetDescriptionContext is like SetDescription but carries the given context
+// through the request.
+func (s Sources) SetDescriptionContext(ctx context.Context, source *MetricSource, description string) error {
+	if source.ID == nil {
+		return fmt.Errorf("source id field not set")
+	}
+
+	payload, err := json.Marshal(description)
+	if err != nil {
+		return err
+	}
+	if err := s.descriptionRequest(ctx, "POST", *source.ID, payload); err != nil {
+		return err
+	}
+	source.Description = description
+	return nil
+}
+
+// RemoveDescription removes the description of an existing MetricSource, and
+// clears the Description of source too.
+// The ID field of the source must be populated
+func (s Sources) RemoveDescription(source *MetricSource) error {
+	return s.RemoveDescriptionContext(context.Background(), source)
+}
+
+// RemoveDescriptionContext is like RemoveDescription but carries the given
+// context through the request.
+func (s Sources) RemoveDescriptionContext(ctx context.Context, source *MetricSource) error {
+	if source.ID == nil {
+		return fmt.Errorf("source id field not set")
+	}
+
+	if err := s.descriptionRequest(ctx, "DELETE", *source.ID, nil); err != nil {
+		return err
+	}
+	source.Description = ""
+	return nil
+}
+
+func (s Sources) descriptionRequest(ctx context.Context, method, id string, payload []byte) error {
+	req, err := newRequestWithContext(ctx, s.client, method, sourcePath(id)+"/description", nil, payload)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	return resp.Close()
+}
+
+// sourcePath returns the API path of the MetricSource with the given ID, which
+// may contain characters that need escaping
+func sourcePath(id string) string {
+	return fmt.Sprintf("%s/%s", baseSourcePath, url.PathEscape(id))
+}
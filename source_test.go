@@ -0,0 +1,133 @@
+package wavefront
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+type MockSourceClient struct {
+	Client
+	InvokedCount int
+	requests     []string
+	T            *testing.T
+}
+
+func (m *MockSourceClient) Do(req *http.Request) (io.ReadCloser, error) {
+	body := ""
+	if req.Body != nil {
+		b, _ := ioutil.ReadAll(req.Body)
+		body = " " + string(b)
+	}
+	m.requests = append(m.requests, req.Method+" "+req.URL.EscapedPath()+body)
+
+	if req.URL.Path == "/api/v2/search/source" {
+		response := `{"response":{"items":[{"id":"web-1","tags":{"role.web":true}}],"moreItems":false}}`
+		return ioutil.NopCloser(bytes.NewReader([]byte(response))), nil
+	}
+	if req.Method != "GET" || req.URL.Path != "/api/v2/source" {
+		return ioutil.NopCloser(bytes.NewReader([]byte(`{"status":{"result":"OK","code":200}}`))), nil
+	}
+
+	expected := ""
+	if m.InvokedCount == 1 {
+		expected = "web-2"
+	}
+	if cursor := req.URL.Query().Get("cursor"); cursor != expected {
+		m.T.Errorf("cursor, expected %q, got %q", expected, cursor)
+	}
+	response, err := ioutil.ReadFile(fmt.Sprintf("./fixtures/paginated-source-%d.json", m.InvokedCount))
+	if err != nil {
+		m.T.Fatal(err)
+	}
+	m.InvokedCount++
+	return ioutil.NopCloser(bytes.NewReader(response)), nil
+}
+
+func newMockSources(t *testing.T) (*Sources, *MockSourceClient) {
+	client := &MockSourceClient{
+		Client: Client{
+			Config:     &Config{Token: "1234-5678-9977"},
+			BaseURL:    &url.URL{Scheme: "http", Host: "testing.wavefront.com"},
+			httpClient: http.DefaultClient,
+		},
+		T: t,
+	}
+	return &Sources{client: client}, client
+}
+
+func TestSources_List(t *testing.T) {
+	s, client := newMockSources(t)
+
+	sources, err := s.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if client.InvokedCount != 2 {
+		t.Errorf("paginated list, expected 2, got %d", client.InvokedCount)
+	}
+	if len(sources) != 2 || *sources[1].ID != "web-2" {
+		t.Fatalf("unexpected sources %+v", sources)
+	}
+	if fmt.Sprint(sources[0].Tags) != "[dc1 role.web]" {
+		t.Errorf("expected tags [dc1 role.web], got %v", sources[0].Tags)
+	}
+	// tags mapped to false are not set
+	if fmt.Sprint(sources[1].Tags) != "[dc2]" {
+		t.Errorf("expected tags [dc2], got %v", sources[1].Tags)
+	}
+}
+
+func TestSources_FindByTag(t *testing.T) {
+	s, client := newMockSources(t)
+
+	sources, err := s.FindByTag("role.web")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sources) != 1 || *sources[0].ID != "web-1" {
+		t.Errorf("expected only web-1, got %+v", sources)
+	}
+
+	// the sources are searched rather than listed
+	expected := `POST /api/v2/search/source {"query":[{"key":"tags","value":"role.web","matchingMethod":"EXACT"}],"limit":100,"offset":0}`
+	if len(client.requests) != 1 || client.requests[0] != expected {
+		t.Errorf("requests expected [%s], got %v", expected, client.requests)
+	}
+}
+
+func TestSources_TagsAndDescription(t *testing.T) {
+	s, client := newMockSources(t)
+
+	if err := s.SetDescription(&MetricSource{}, "x"); err == nil {
+		t.Error("expected setting a description with no ID to error")
+	}
+
+	id := "web 1"
+	source := &MetricSource{ID: &id}
+	if err := s.AddTag(source, "role.web"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetDescription(source, "Web server"); err != nil {
+		t.Fatal(err)
+	}
+	if source.Description != "Web server" || len(source.Tags) != 1 {
+		t.Errorf("expected the source to be updated, got %+v", source)
+	}
+	if err := s.RemoveDescription(source); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"PUT /api/v2/source/web%201/tag/role.web",
+		`POST /api/v2/source/web%201/description "Web server"`,
+		"DELETE /api/v2/source/web%201/description",
+	}
+	if fmt.Sprint(client.requests) != fmt.Sprint(expected) {
+		t.Errorf("requests expected %v, got %v", expected, client.requests)
+	}
+}
@@ -0,0 +1,229 @@
+package wavefront
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// TagChange is a change to the tags of an entity. The tags in Add are added,
+// then the tags in Remove are removed.
+type TagChange struct {
+	Add    []string
+	Remove []string
+}
+
+// TagResult is the outcome of applying a TagChange to a single entity
+type TagResult struct {
+	// ID is the ID of the entity
+	ID string
+
+	// Err is set if the tags of the entity could not be changed
+	Err error
+}
+
+// Tags is used to change the tags of many entities at once. The tags of a
+// single entity are changed through its service, e.g. Alerts.AddTag.
+type Tags struct {
+	// client is the Wavefront client used to perform tag-related operations
+	client Wavefronter
+}
+
+// defaultTagWorkers is the number of entities changed at once if no number
+// is given
+const defaultTagWorkers = 4
+
+// taggablePaths are the base API paths of the entities with tag endpoints,
+// by their search type
+var taggablePaths = map[string]string{
+	"alert":         baseAlertPath,
+	"dashboard":     baseDashboardPath,
+	"derivedmetric": baseDerivedMetricPath,
+	"event":         baseEventPath,
+}
+
+// Tags is used to return a client for bulk tag operations
+func (c *Client) Tags() *Tags {
+	return &Tags{client: c}
+}
+
+// ChangeMatching applies change to every entity of the given type, e.g.
+// "alert", matching the given search conditions. At most workers entities are
+// changed at once, or 4 if workers is not positive. A result is returned for
+// every matching entity; the error is only set if the search itself fails.
+// filter must not be empty, to avoid changing every entity by mistake.
+func (t Tags) ChangeMatching(entityType string, filter []*SearchCondition, change TagChange, workers int) ([]*TagResult, error) {
+	return t.ChangeMatchingContext(context.Background(), entityType, filter, change, workers)
+}
+
+// ChangeMatchingContext is like ChangeMatching but carries the given context
+// through the search and every tag request. Entities not yet changed when the
+// context is done have the context's error as their result.
+func (t Tags) ChangeMatchingContext(ctx context.Context, entityType string, filter []*SearchCondition, change TagChange, workers int) ([]*TagResult, error) {
+	basePath, ok := taggablePaths[entityType]
+	if !ok {
+		types := make([]string, 0, len(taggablePaths))
+		for k := range taggablePaths {
+			types = append(types, k)
+		}
+		sort.Strings(types)
+		return nil, fmt.Errorf("%s does not support tags, expected one of %s", entityType, strings.Join(types, ", "))
+	}
+	if len(filter) == 0 {
+		return nil, fmt.Errorf("no search conditions given")
+	}
+	if workers <= 0 {
+		workers = defaultTagWorkers
+	}
+
+	ids, err := t.findIDs(ctx, entityType, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*TagResult, len(ids))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				path := fmt.Sprintf("%s/%s", basePath, ids[j])
+				results[j] = &TagResult{ID: ids[j], Err: applyTagChange(ctx, t.client, path, change)}
+			}
+		}()
+	}
+	for j := range ids {
+		jobs <- j
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results, nil
+}
+
+// findIDs returns the IDs of every entity of the given type matching filter
+func (t Tags) findIDs(ctx context.Context, entityType string, filter []*SearchCondition) ([]string, error) {
+	search := &Search{
+		client: t.client,
+		Type:   entityType,
+		Params: &SearchParams{
+			Conditions: filter,
+		},
+	}
+
+	var ids []string
+	moreItems := true
+	for moreItems == true {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		resp, err := search.ExecuteContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		var tmpres []struct {
+			ID string `json:"id"`
+		}
+		err = json.Unmarshal(resp.Response.Items, &tmpres)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range tmpres {
+			ids = append(ids, e.ID)
+		}
+		moreItems = resp.Response.MoreItems
+		search.Params.Offset = resp.NextOffset
+	}
+
+	return ids, nil
+}
+
+// applyTagChange applies change to the entity at path, stopping at the first
+// error
+func applyTagChange(ctx context.Context, client Wavefronter, path string, change TagChange) error {
+	for _, tag := range change.Add {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := addTag(ctx, client, path, tag); err != nil {
+			return err
+		}
+	}
+	for _, tag := range change.Remove {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := removeTag(ctx, client, path, tag); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addTag adds tag to the entity at path
+func addTag(ctx context.Context, client Wavefronter, path, tag string) error {
+	if tag == "" {
+		return fmt.Errorf("tag must not be empty")
+	}
+	return tagRequest(ctx, client, "PUT", fmt.Sprintf("%s/tag/%s", path, url.PathEscape(tag)), nil)
+}
+
+// removeTag removes tag from the entity at path
+func removeTag(ctx context.Context, client Wavefronter, path, tag string) error {
+	if tag == "" {
+		return fmt.Errorf("tag must not be empty")
+	}
+	return tagRequest(ctx, client, "DELETE", fmt.Sprintf("%s/tag/%s", path, url.PathEscape(tag)), nil)
+}
+
+// setTags replaces the tags of the entity at path
+func setTags(ctx context.Context, client Wavefronter, path string, tags []string) error {
+	if tags == nil {
+		tags = []string{}
+	}
+	payload, err := json.Marshal(tags)
+	if err != nil {
+		return err
+	}
+	return tagRequest(ctx, client, "POST", path+"/tag", payload)
+}
+
+func tagRequest(ctx context.Context, client Wavefronter, method, path string, payload []byte) error {
+	req, err := newRequestWithContext(ctx, client, method, path, nil, payload)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	return resp.Close()
+}
+
+// withTag returns tags with tag appended, if it is not already present
+func withTag(tags []string, tag string) []string {
+	for _, t := range tags {
+		if t == tag {
+			return tags
+		}
+	}
+	return append(tags, tag)
+}
+
+// withoutTag returns tags without tag
+func withoutTag(tags []string, tag string) []string {
+	out := make([]string, 0, len(tags))
+	for _, t := range tags {
+		if t != tag {
+			out = append(out, t)
+		}
+	}
+	return out
+}
@@ -0,0 +1,170 @@
+package wavefront
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type MockTagClient struct {
+	Client
+	mu       sync.Mutex
+	requests []string
+	active   int
+	peak     int
+	T        *testing.T
+}
+
+func (m *MockTagClient) Do(req *http.Request) (io.ReadCloser, error) {
+	if strings.HasPrefix(req.URL.Path, "/api/v2/search/") {
+		search := `{"response":{"items":[{"id":"1"},{"id":"2"},{"id":"3"},{"id":"fail"}],"moreItems":false}}`
+		return ioutil.NopCloser(strings.NewReader(search)), nil
+	}
+
+	m.mu.Lock()
+	body := ""
+	if req.Body != nil {
+		b, _ := ioutil.ReadAll(req.Body)
+		body = " " + string(b)
+	}
+	m.requests = append(m.requests, req.Method+" "+req.URL.EscapedPath()+body)
+	m.active++
+	if m.active > m.peak {
+		m.peak = m.active
+	}
+	m.mu.Unlock()
+
+	defer func() {
+		m.mu.Lock()
+		m.active--
+		m.mu.Unlock()
+	}()
+	if strings.Contains(req.URL.Path, "/fail/") {
+		return nil, fmt.Errorf("failed")
+	}
+	return ioutil.NopCloser(bytes.NewReader([]byte(`{"status":{"result":"OK","code":200}}`))), nil
+}
+
+func newMockTagClient(t *testing.T) *MockTagClient {
+	return &MockTagClient{
+		Client: Client{
+			Config:     &Config{Token: "1234-5678-9977"},
+			BaseURL:    &url.URL{Scheme: "http", Host: "testing.wavefront.com"},
+			httpClient: http.DefaultClient,
+		},
+		T: t,
+	}
+}
+
+func TestAlerts_Tags(t *testing.T) {
+	client := newMockTagClient(t)
+	a := &Alerts{client: client}
+
+	if err := a.AddTag(&Alert{}, "team.db"); err == nil {
+		t.Error("expected adding a tag with no ID to error")
+	}
+
+	id := "1234"
+	alert := &Alert{ID: &id, Tags: []string{"dc1"}}
+	if err := a.AddTag(alert, "team.db"); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.AddTag(alert, "team.db"); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(alert.Tags) != "[dc1 team.db]" {
+		t.Errorf("expected tags [dc1 team.db], got %v", alert.Tags)
+	}
+	if err := a.RemoveTag(alert, "dc1"); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(alert.Tags) != "[team.db]" {
+		t.Errorf("expected tags [team.db], got %v", alert.Tags)
+	}
+	if err := a.SetTags(alert, "a/b", "c"); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.AddTag(alert, ""); err == nil {
+		t.Error("expected adding an empty tag to error")
+	}
+
+	expected := []string{
+		"PUT /api/v2/alert/1234/tag/team.db",
+		"PUT /api/v2/alert/1234/tag/team.db",
+		"DELETE /api/v2/alert/1234/tag/dc1",
+		`POST /api/v2/alert/1234/tag ["a/b","c"]`,
+	}
+	if fmt.Sprint(client.requests) != fmt.Sprint(expected) {
+		t.Errorf("requests expected %v, got %v", expected, client.requests)
+	}
+}
+
+func TestEvents_SetTags(t *testing.T) {
+	client := newMockTagClient(t)
+	e := &Events{client: client}
+
+	id := "1234"
+	event := &Event{ID: &id, Tags: []string{"deploy"}}
+	if err := e.SetTags(event); err != nil {
+		t.Fatal(err)
+	}
+	if len(event.Tags) != 0 {
+		t.Errorf("expected no tags, got %v", event.Tags)
+	}
+	if client.requests[0] != "POST /api/v2/event/1234/tag []" {
+		t.Errorf("unexpected request %s", client.requests[0])
+	}
+}
+
+func TestTags_ChangeMatching(t *testing.T) {
+	client := newMockTagClient(t)
+	tags := &Tags{client: client}
+
+	if _, err := tags.ChangeMatching("maintenancewindow", []*SearchCondition{{Key: "name", Value: "x"}}, TagChange{}, 1); err == nil {
+		t.Error("expected an untaggable entity type to error")
+	}
+	if _, err := tags.ChangeMatching("alert", nil, TagChange{}, 1); err == nil {
+		t.Error("expected changing with no search conditions to error")
+	}
+
+	filter := []*SearchCondition{{Key: "tags", Value: "team.db", MatchingMethod: "EXACT"}}
+	change := TagChange{Add: []string{"team.storage"}, Remove: []string{"team.db"}}
+	results, err := tags.ChangeMatching("dashboard", filter, change, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(results) != 4 {
+		t.Fatalf("expected 4 results, got %d", len(results))
+	}
+	for _, result := range results {
+		if (result.ID == "fail") != (result.Err != nil) {
+			t.Errorf("%s: unexpected error %v", result.ID, result.Err)
+		}
+	}
+
+	// a failed add is not followed by a remove
+	sort.Strings(client.requests)
+	expected := []string{
+		"DELETE /api/v2/dashboard/1/tag/team.db",
+		"DELETE /api/v2/dashboard/2/tag/team.db",
+		"DELETE /api/v2/dashboard/3/tag/team.db",
+		"PUT /api/v2/dashboard/1/tag/team.storage",
+		"PUT /api/v2/dashboard/2/tag/team.storage",
+		"PUT /api/v2/dashboard/3/tag/team.storage",
+		"PUT /api/v2/dashboard/fail/tag/team.storage",
+	}
+	if fmt.Sprint(client.requests) != fmt.Sprint(expected) {
+		t.Errorf("requests expected %v, got %v", expected, client.requests)
+	}
+	if client.peak > 2 {
+		t.Errorf("expected at most 2 concurrent requests, got %d", client.peak)
+	}
+}
@@ -1,9 +1,11 @@
 package wavefront
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"reflect"
 )
 
 // Target represents a Wavefront Alert Target, for routing notifications
@@ -52,6 +54,10 @@ type Target struct {
 	// ALERT_AFFECTED_BY_MAINTENANCE_WINDOW, ALERT_SNOOZED, ALERT_NO_DATA,
 	// ALERT_NO_DATA_RESOLVED
 	Triggers []string `json:"triggers"`
+
+	// unknown holds properties returned by Wavefront that are not modelled
+	// above, so that they survive a Get followed by an Update
+	unknown map[string]json.RawMessage
 }
 
 // Targets is used to perform target-related operations against the Wavefront API
@@ -62,6 +68,31 @@ type Targets struct {
 
 const baseTargetPath = "/api/v2/notificant"
 
+// targetFields are the JSON properties modelled by Target
+var targetFields = jsonFieldNames(reflect.TypeOf(Target{}))
+
+// UnmarshalJSON is a custom JSON unmarshaller for a Target, used in order to
+// keep properties that are not modelled by Target
+func (t *Target) UnmarshalJSON(b []byte) error {
+	type target Target
+	if err := json.Unmarshal(b, (*target)(t)); err != nil {
+		return err
+	}
+
+	var err error
+	t.unknown, err = unknownFields(b, targetFields)
+	return err
+}
+
+func (t *Target) MarshalJSON() ([]byte, error) {
+	type target Target
+	b, err := json.Marshal((*target)(t))
+	if err != nil {
+		return nil, err
+	}
+	return withUnknownFields(b, t.unknown)
+}
+
 // Targets is used to return a client for target-related operations
 func (c *Client) Targets() *Targets {
 	return &Targets{client: c}
@@ -70,16 +101,27 @@ func (c *Client) Targets() *Targets {
 // Get is used to retrieve an existing Target by ID.
 // The ID field must be provided
 func (t Targets) Get(target *Target) error {
+	return t.GetContext(context.Background(), target)
+}
+
+// GetContext is like Get but carries the given context through the request.
+func (t Targets) GetContext(ctx context.Context, target *Target) error {
 	if *target.ID == "" {
 		return fmt.Errorf("Target id field is not set")
 	}
 
-	return t.crudTarget("GET", fmt.Sprintf("%s/%s", baseTargetPath, *target.ID), target)
+	return t.crudTarget(ctx, "GET", fmt.Sprintf("%s/%s", baseTargetPath, *target.ID), target)
 }
 
 // Find returns all targets filtered by the given search conditions.
 // If filter is nil, all targets are returned.
 func (t Targets) Find(filter []*SearchCondition) ([]*Target, error) {
+	return t.FindContext(context.Background(), filter)
+}
+
+// FindContext is like Find but carries the given context through every page
+// of the search. Pagination stops as soon as the context is done.
+func (t Targets) FindContext(ctx context.Context, filter []*SearchCondition) ([]*Target, error) {
 	search := &Search{
 		client: t.client,
 		Type:   "notificant",
@@ -91,7 +133,10 @@ func (t Targets) Find(filter []*SearchCondition) ([]*Target, error) {
 	var results []*Target
 	moreItems := true
 	for moreItems == true {
-		resp, err := search.Execute()
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		resp, err := search.ExecuteContext(ctx)
 		if err != nil {
 			return nil, err
 		}
@@ -111,28 +156,43 @@ func (t Targets) Find(filter []*SearchCondition) ([]*Target, error) {
 // Create is used to create a Target in Wavefront.
 // If successful, the ID field of the target will be populated.
 func (t Targets) Create(target *Target) error {
-	return t.crudTarget("POST", baseTargetPath, target)
+	return t.CreateContext(context.Background(), target)
+}
+
+// CreateContext is like Create but carries the given context through the request.
+func (t Targets) CreateContext(ctx context.Context, target *Target) error {
+	return t.crudTarget(ctx, "POST", baseTargetPath, target)
 }
 
 // Update is used to update an existing Target.
 // The ID field of the target must be populated
 func (t Targets) Update(target *Target) error {
+	return t.UpdateContext(context.Background(), target)
+}
+
+// UpdateContext is like Update but carries the given context through the request.
+func (t Targets) UpdateContext(ctx context.Context, target *Target) error {
 	if target.ID == nil {
 		return fmt.Errorf("target id field not set")
 	}
 
-	return t.crudTarget("PUT", fmt.Sprintf("%s/%s", baseTargetPath, *target.ID), target)
+	return t.crudTarget(ctx, "PUT", fmt.Sprintf("%s/%s", baseTargetPath, *target.ID), target)
 
 }
 
 // Delete is used to delete an existing Target.
 // The ID field of the target must be populated
 func (t Targets) Delete(target *Target) error {
+	return t.DeleteContext(context.Background(), target)
+}
+
+// DeleteContext is like Delete but carries the given context through the request.
+func (t Targets) DeleteContext(ctx context.Context, target *Target) error {
 	if target.ID == nil {
 		return fmt.Errorf("target id field not set")
 	}
 
-	err := t.crudTarget("DELETE", fmt.Sprintf("%s/%s", baseTargetPath, *target.ID), target)
+	err := t.crudTarget(ctx, "DELETE", fmt.Sprintf("%s/%s", baseTargetPath, *target.ID), target)
 	if err != nil {
 		return err
 	}
@@ -143,12 +203,12 @@ func (t Targets) Delete(target *Target) error {
 
 }
 
-func (t Targets) crudTarget(method, path string, target *Target) error {
+func (t Targets) crudTarget(ctx context.Context, method, path string, target *Target) error {
 	payload, err := json.Marshal(target)
 	if err != nil {
 		return err
 	}
-	req, err := t.client.NewRequest(method, path, nil, payload)
+	req, err := newRequestWithContext(ctx, t.client, method, path, nil, payload)
 	if err != nil {
 		return err
 	}
@@ -2,6 +2,7 @@ package wavefront
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"io"
 	"io/ioutil"
@@ -12,7 +13,8 @@ import (
 
 type MockTargetClient struct {
 	Client
-	T *testing.T
+	InvokedCount int
+	T            *testing.T
 }
 
 type MockCrudTargetClient struct {
@@ -26,6 +28,7 @@ func (m *MockTargetClient) Do(req *http.Request) (io.ReadCloser, error) {
 	if err != nil {
 		m.T.Fatal(err)
 	}
+	m.InvokedCount++
 	return ioutil.NopCloser(bytes.NewReader(response)), nil
 }
 
@@ -56,6 +59,32 @@ func TestTargets_Find(t *testing.T) {
 	}
 }
 
+func TestTargets_FindContextCancelled(t *testing.T) {
+	baseurl, _ := url.Parse("http://testing.wavefront.com")
+	tgts := &Targets{
+		client: &MockTargetClient{
+			Client: Client{
+				Config:     &Config{Token: "1234-5678-9977"},
+				BaseURL:    baseurl,
+				httpClient: http.DefaultClient,
+				debug:      true,
+			},
+			T: t,
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := tgts.FindContext(ctx, nil); err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	invoked := ((tgts.client).(*MockTargetClient)).InvokedCount
+	if invoked != 0 {
+		t.Errorf("paginated search with cancelled context, expected 0 requests, got %d", invoked)
+	}
+}
+
 func (m *MockCrudTargetClient) Do(req *http.Request) (io.ReadCloser, error) {
 	response, err := ioutil.ReadFile("./fixtures/create-target-response.json")
 	if err != nil {
@@ -0,0 +1,306 @@
+package wavefront
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultRefreshMargin is how long before expiry a refreshing token is renewed
+const defaultRefreshMargin = 30 * time.Second
+
+// TokenProvider supplies the authentication token for each request made by a
+// Client. Implementations must be safe for concurrent use.
+type TokenProvider interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// TokenInvalidator is implemented by TokenProviders that cache tokens. When
+// the API rejects a token with a 401, the Client calls Invalidate with that
+// token and retries the request once with a fresh one.
+type TokenInvalidator interface {
+	Invalidate(token string)
+}
+
+// authorize sets the Authorization header of req from the configured
+// TokenProvider, returning the token used.
+func (c Client) authorize(req *http.Request) (string, error) {
+	if c.Config.TokenProvider == nil {
+		return "", nil
+	}
+	token, err := c.Config.TokenProvider.Token(req.Context())
+	if err != nil {
+		return "", fmt.Errorf("error obtaining token: %s", err)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	return token, nil
+}
+
+// invalidateToken discards a rejected token, reporting whether a fresh one
+// may be available.
+func (c Client) invalidateToken(token string) bool {
+	inv, ok := c.Config.TokenProvider.(TokenInvalidator)
+	if !ok {
+		return false
+	}
+	inv.Invalidate(token)
+	return true
+}
+
+// StaticToken is a TokenProvider that always returns the same token
+type StaticToken string
+
+// Token returns the token
+func (t StaticToken) Token(ctx context.Context) (string, error) {
+	return string(t), nil
+}
+
+// FileTokenProvider reads the token from a file, such as one mounted by a
+// secret manager. The file is re-read whenever its size or modification time
+// changes, so rotated tokens are picked up without restarting.
+type FileTokenProvider struct {
+	// Path is the path of the file holding the token. Surrounding whitespace
+	// is ignored.
+	Path string
+
+	mu      sync.Mutex
+	token   string
+	modTime time.Time
+	size    int64
+}
+
+// NewFileTokenProvider returns a FileTokenProvider reading from path
+func NewFileTokenProvider(path string) *FileTokenProvider {
+	return &FileTokenProvider{Path: path}
+}
+
+// Token returns the current contents of the token file
+func (p *FileTokenProvider) Token(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	info, err := os.Stat(p.Path)
+	if err != nil {
+		return "", err
+	}
+	if p.token != "" && info.ModTime().Equal(p.modTime) && info.Size() == p.size {
+		return p.token, nil
+	}
+
+	b, err := ioutil.ReadFile(p.Path)
+	if err != nil {
+		return "", err
+	}
+	token := strings.TrimSpace(string(b))
+	if token == "" {
+		return "", fmt.Errorf("token file %s is empty", p.Path)
+	}
+	p.token, p.modTime, p.size = token, info.ModTime(), info.Size()
+	return p.token, nil
+}
+
+// Invalidate forces the token file to be re-read on the next request
+func (p *FileTokenProvider) Invalidate(token string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.token == token {
+		p.token = ""
+	}
+}
+
+// CommandTokenProvider obtains the token by running a command and reading its
+// standard output, e.g. a secret manager CLI.
+type CommandTokenProvider struct {
+	// Command is the program to run
+	Command string
+
+	// Args are the arguments passed to Command
+	Args []string
+
+	// CacheFor is how long the output of the command is reused before the
+	// command is run again. If zero, the command is run for every request.
+	CacheFor time.Duration
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// NewCommandTokenProvider returns a CommandTokenProvider running the given
+// command, caching its output for cacheFor.
+func NewCommandTokenProvider(cacheFor time.Duration, command string, args ...string) *CommandTokenProvider {
+	return &CommandTokenProvider{
+		Command:  command,
+		Args:     args,
+		CacheFor: cacheFor,
+	}
+}
+
+// Token returns the output of the command, running it if the cached token
+// has expired
+func (p *CommandTokenProvider) Token(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.token != "" && time.Now().Before(p.expires) {
+		return p.token, nil
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, p.Command, p.Args...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("token command %s failed: %s: %s", p.Command, err, strings.TrimSpace(stderr.String()))
+	}
+	token := strings.TrimSpace(string(out))
+	if token == "" {
+		return "", fmt.Errorf("token command %s returned no output", p.Command)
+	}
+	p.token, p.expires = token, time.Now().Add(p.CacheFor)
+	return p.token, nil
+}
+
+// Invalidate forces the command to be run again on the next request
+func (p *CommandTokenProvider) Invalidate(token string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.token == token {
+		p.token = ""
+	}
+}
+
+// RefreshFunc obtains a new short-lived token and the time at which it expires
+type RefreshFunc func(ctx context.Context) (token string, expires time.Time, err error)
+
+// RefreshingTokenProvider caches a short-lived token obtained from Refresh
+// and renews it shortly before it expires, or when the API rejects it.
+type RefreshingTokenProvider struct {
+	// Refresh obtains a new token
+	Refresh RefreshFunc
+
+	// Margin is how long before expiry the token is renewed. Defaults to 30s.
+	Margin time.Duration
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+
+	// now is the clock used by the provider, overridden in tests
+	now func() time.Time
+}
+
+// NewRefreshingTokenProvider returns a RefreshingTokenProvider using refresh
+func NewRefreshingTokenProvider(refresh RefreshFunc) *RefreshingTokenProvider {
+	return &RefreshingTokenProvider{
+		Refresh: refresh,
+		Margin:  defaultRefreshMargin,
+		now:     time.Now,
+	}
+}
+
+// Token returns the cached token, refreshing it if it is close to expiry
+func (p *RefreshingTokenProvider) Token(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now
+	if p.now != nil {
+		now = p.now
+	}
+	if p.token != "" && now().Add(p.Margin).Before(p.expires) {
+		return p.token, nil
+	}
+
+	token, expires, err := p.Refresh(ctx)
+	if err != nil {
+		return "", err
+	}
+	p.token, p.expires = token, expires
+	return p.token, nil
+}
+
+// Invalidate discards the cached token if it is the one given
+func (p *RefreshingTokenProvider) Invalidate(token string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.token == token {
+		p.token = ""
+	}
+}
+
+// TokenExchange exchanges a long-lived API token for short-lived access
+// tokens using an OAuth-style refresh token grant. Its Exchange method can be
+// used as the RefreshFunc of a RefreshingTokenProvider.
+type TokenExchange struct {
+	// URL is the token endpoint, e.g. a CSP authorize endpoint
+	URL string
+
+	// APIToken is the long-lived token sent as the refresh_token
+	APIToken string
+
+	// HTTPClient is used to call URL. Defaults to http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+// NewTokenExchangeProvider returns a RefreshingTokenProvider that exchanges
+// apiToken for access tokens at the given token endpoint.
+func NewTokenExchangeProvider(tokenURL, apiToken string) *RefreshingTokenProvider {
+	x := &TokenExchange{URL: tokenURL, APIToken: apiToken}
+	return NewRefreshingTokenProvider(x.Exchange)
+}
+
+// Exchange requests a new access token
+func (x *TokenExchange) Exchange(ctx context.Context) (string, time.Time, error) {
+	form := url.Values{}
+	form.Set("grant_type", "refresh_token")
+	form.Set("refresh_token", x.APIToken)
+
+	req, err := http.NewRequest("POST", x.URL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	client := x.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	issued := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if resp.StatusCode != 200 {
+		return "", time.Time{}, fmt.Errorf("token exchange returned %s: %s", resp.Status, string(body))
+	}
+
+	var tr struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &tr); err != nil {
+		return "", time.Time{}, err
+	}
+	if tr.AccessToken == "" {
+		return "", time.Time{}, fmt.Errorf("token exchange returned no access_token")
+	}
+	return tr.AccessToken, issued.Add(time.Duration(tr.ExpiresIn) * time.Second), nil
+}
@@ -0,0 +1,145 @@
+package wavefront
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClientTokenProvider_RetryOn401(t *testing.T) {
+	calls := 0
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.Header.Get("Authorization") != "Bearer token-2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	refreshes := 0
+	provider := NewRefreshingTokenProvider(func(ctx context.Context) (string, time.Time, error) {
+		refreshes++
+		return fmt.Sprintf("token-%d", refreshes), time.Now().Add(time.Hour), nil
+	})
+
+	client, err := NewClient(&Config{
+		Address:       strings.TrimLeft(srv.URL, "https://"),
+		TokenProvider: provider,
+		SkipTLSVerify: true,
+	})
+	if err != nil {
+		t.Fatal("error initiating client:", err)
+	}
+
+	req, _ := client.NewRequest("PUT", "test/thing", nil, []byte(`{}`))
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal("error executing request:", err)
+	}
+	resp.Close()
+
+	if calls != 2 || refreshes != 2 {
+		t.Errorf("expected one retry with a refreshed token, got %d calls and %d refreshes", calls, refreshes)
+	}
+
+	// a token that keeps being rejected is only retried once
+	calls = 0
+	provider.Refresh = func(ctx context.Context) (string, time.Time, error) {
+		return "bad", time.Now().Add(time.Hour), nil
+	}
+	provider.Invalidate("token-2")
+	req, _ = client.NewRequest("GET", "test/thing", nil, nil)
+	if _, err := client.Do(req); !IsUnauthorized(err) {
+		t.Errorf("expected unauthorized error, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected a single retry on 401, got %d calls", calls)
+	}
+}
+
+func TestRefreshingTokenProvider(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	refreshes := 0
+	p := NewRefreshingTokenProvider(func(ctx context.Context) (string, time.Time, error) {
+		refreshes++
+		return fmt.Sprintf("token-%d", refreshes), now.Add(10 * time.Minute), nil
+	})
+	p.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		if token, _ := p.Token(context.Background()); token != "token-1" {
+			t.Errorf("expected cached token-1, got %s", token)
+		}
+	}
+
+	// renewed within the margin of expiry
+	now = now.Add(10*time.Minute - time.Second)
+	if token, _ := p.Token(context.Background()); token != "token-2" {
+		t.Errorf("expected refreshed token-2, got %s", token)
+	}
+}
+
+func TestFileTokenProvider(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wavefront-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "token")
+	ioutil.WriteFile(path, []byte("first\n"), 0600)
+
+	p := NewFileTokenProvider(path)
+	if token, err := p.Token(context.Background()); err != nil || token != "first" {
+		t.Errorf("expected 'first', got '%s' (%v)", token, err)
+	}
+
+	ioutil.WriteFile(path, []byte("rotated\n"), 0600)
+	if token, err := p.Token(context.Background()); err != nil || token != "rotated" {
+		t.Errorf("expected 'rotated', got '%s' (%v)", token, err)
+	}
+}
+
+func TestCommandTokenProvider(t *testing.T) {
+	p := NewCommandTokenProvider(time.Minute, "echo", "abc123")
+	if token, err := p.Token(context.Background()); err != nil || token != "abc123" {
+		t.Errorf("expected 'abc123', got '%s' (%v)", token, err)
+	}
+
+	p = NewCommandTokenProvider(0, "false")
+	if _, err := p.Token(context.Background()); err == nil {
+		t.Error("expected failing command to return an error")
+	}
+}
+
+func TestTokenExchange(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.Form.Get("refresh_token") != "api-token" {
+			t.Errorf("refresh_token, expected api-token, got %s", r.Form.Get("refresh_token"))
+		}
+		w.Write([]byte(`{"access_token":"short-lived","expires_in":1800}`))
+	}))
+	defer srv.Close()
+
+	x := &TokenExchange{URL: srv.URL, APIToken: "api-token"}
+	token, expires, err := x.Exchange(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "short-lived" {
+		t.Errorf("expected 'short-lived', got %s", token)
+	}
+	if d := time.Until(expires); d < 29*time.Minute || d > 30*time.Minute {
+		t.Errorf("expected expiry in 30 minutes, got %s", d)
+	}
+}
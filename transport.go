@@ -0,0 +1,103 @@
+package wavefront
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// Middleware wraps a RoundTripper to add behaviour to every request made by a
+// Client, such as auth headers, tracing, metrics or request signing.
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+// RoundTripperFunc adapts an ordinary function to an http.RoundTripper, which
+// is convenient when writing a Middleware.
+type RoundTripperFunc func(req *http.Request) (*http.Response, error)
+
+// RoundTrip calls f(req)
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// newTransport builds the RoundTripper used by a Client. The TLS and proxy
+// settings in config are applied to the base transport, which is wrapped by
+// the cassette, if any, and then the middleware chain.
+func newTransport(config *Config) (http.RoundTripper, error) {
+	var rt http.RoundTripper
+	var err error
+	// replaying a cassette never touches the network
+	if config.Cassette == nil || config.Cassette.Mode != CassetteReplay {
+		rt, err = newBaseTransport(config)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if config.Cassette != nil {
+		var redact []string
+		if config.Logging != nil {
+			redact = config.Logging.RedactHeaders
+		}
+		rt, err = config.Cassette.roundTripper(rt, redact)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return wrapMiddleware(rt, config.Middleware), nil
+}
+
+// newBaseTransport applies the TLS and proxy settings in config to the
+// configured base transport.
+func newBaseTransport(config *Config) (http.RoundTripper, error) {
+	var t *http.Transport
+	switch base := config.Transport.(type) {
+	case nil:
+		t = &http.Transport{
+			TLSNextProto: map[string]func(authority string, c *tls.Conn) http.RoundTripper{},
+		}
+	case *http.Transport:
+		// never modify a transport the caller may be sharing
+		t = base.Clone()
+	default:
+		if config.TLSClientConfig != nil || config.HttpProxy != "" || config.SkipTLSVerify {
+			return nil, fmt.Errorf("TLSClientConfig, HttpProxy and SkipTLSVerify require Transport to be an *http.Transport, got %T", base)
+		}
+		return base, nil
+	}
+
+	if config.TLSClientConfig != nil {
+		t.TLSClientConfig = config.TLSClientConfig
+	}
+
+	// ENABLE HTTP Proxy
+	if config.HttpProxy != "" {
+		proxyUrl, err := url.Parse(config.HttpProxy)
+		if err != nil {
+			return nil, err
+		}
+		t.Proxy = http.ProxyURL(proxyUrl)
+	}
+
+	//For testing ONLY
+	if config.SkipTLSVerify == true {
+		tlsConfig := &tls.Config{}
+		if t.TLSClientConfig != nil {
+			tlsConfig = t.TLSClientConfig.Clone()
+		}
+		tlsConfig.InsecureSkipVerify = true
+		t.TLSClientConfig = tlsConfig
+	}
+
+	return t, nil
+}
+
+// wrapMiddleware wraps rt in the given middleware. The first middleware is
+// the outermost, so it sees each request first and each response last.
+func wrapMiddleware(rt http.RoundTripper, middleware []Middleware) http.RoundTripper {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		rt = middleware[i](rt)
+	}
+	return rt
+}
@@ -0,0 +1,111 @@
+package wavefront
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClientMiddleware(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("X-Order"); got != "first,second" {
+			t.Errorf("middleware order, expected 'first,second', got '%s'", got)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	tag := func(name string) Middleware {
+		return func(next http.RoundTripper) http.RoundTripper {
+			return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				if v := req.Header.Get("X-Order"); v != "" {
+					name = v + "," + name
+				}
+				req.Header.Set("X-Order", name)
+				return next.RoundTrip(req)
+			})
+		}
+	}
+
+	client, err := NewClient(&Config{
+		Address:       strings.TrimLeft(srv.URL, "https://"),
+		Token:         "123456789",
+		SkipTLSVerify: true,
+		Middleware:    []Middleware{tag("first"), tag("second")},
+	})
+	if err != nil {
+		t.Fatal("error initiating client:", err)
+	}
+
+	req, _ := client.NewRequest("GET", "test/thing", nil, nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal("error executing request:", err)
+	}
+	resp.Close()
+}
+
+func TestClientSkipTLSVerifyWithProxy(t *testing.T) {
+	tlsConfig := &tls.Config{ServerName: "example.org"}
+	httpProxy := "http://example.com:8080"
+
+	client, err := NewClient(&Config{
+		Address:         "example.org",
+		Token:           "123456789",
+		TLSClientConfig: tlsConfig,
+		HttpProxy:       httpProxy,
+		SkipTLSVerify:   true,
+	})
+	if err != nil {
+		t.Fatal("error initiating client:", err)
+	}
+
+	transport := client.httpClient.Transport.(*http.Transport)
+	if !transport.TLSClientConfig.InsecureSkipVerify {
+		t.Error("expected InsecureSkipVerify to be set")
+	}
+	if transport.TLSClientConfig.ServerName != "example.org" {
+		t.Error("TLS Client Config not preserved with SkipTLSVerify")
+	}
+	if tlsConfig.InsecureSkipVerify {
+		t.Error("expected caller's TLS Client Config not to be modified")
+	}
+
+	transportProxy, _ := transport.Proxy(nil)
+	if transportProxy == nil || httpProxy != transportProxy.String() {
+		t.Error("HttpProxy not preserved with SkipTLSVerify")
+	}
+}
+
+func TestClientBaseTransport(t *testing.T) {
+	base := &http.Transport{MaxIdleConns: 7}
+	client, err := NewClient(&Config{
+		Address:   "example.org",
+		Transport: base,
+		HttpProxy: "http://example.com:8080",
+	})
+	if err != nil {
+		t.Fatal("error initiating client:", err)
+	}
+
+	transport := client.httpClient.Transport.(*http.Transport)
+	if transport == base || base.Proxy != nil {
+		t.Error("expected base transport to be copied, not modified")
+	}
+	if transport.MaxIdleConns != 7 {
+		t.Error("base transport settings not preserved")
+	}
+
+	_, err = NewClient(&Config{
+		Address: "example.org",
+		Transport: RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return nil, nil
+		}),
+		HttpProxy: "http://example.com:8080",
+	})
+	if err == nil {
+		t.Error("expected error configuring a proxy on a custom RoundTripper")
+	}
+}
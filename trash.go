@@ -0,0 +1,18 @@
+package wavefront
+
+import "context"
+
+// deletePermanently deletes the entity at path, skipping the trash
+func deletePermanently(ctx context.Context, client Wavefronter, path string) error {
+	params := map[string]string{"skipTrash": "true"}
+	req, err := newRequestWithContext(ctx, client, "DELETE", path, &params, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	return resp.Close()
+}
@@ -0,0 +1,117 @@
+package wavefront
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+type MockTrashClient struct {
+	Client
+	requests []*http.Request
+	T        *testing.T
+}
+
+func (m *MockTrashClient) Do(req *http.Request) (io.ReadCloser, error) {
+	m.requests = append(m.requests, req)
+	fixture := "./fixtures/create-alert-response.json"
+	if req.URL.Path == "/api/v2/search/alert/deleted" {
+		fixture = "./fixtures/paginated-alert-1.json"
+	}
+	response, err := ioutil.ReadFile(fixture)
+	if err != nil {
+		m.T.Fatal(err)
+	}
+	return ioutil.NopCloser(bytes.NewReader(response)), nil
+}
+
+func (m *MockTrashClient) last() *http.Request {
+	return m.requests[len(m.requests)-1]
+}
+
+func TestAlerts_Trash(t *testing.T) {
+	baseurl, _ := url.Parse("http://testing.wavefront.com")
+	client := &MockTrashClient{
+		Client: Client{
+			Config:     &Config{Token: "1234-5678-9977"},
+			BaseURL:    baseurl,
+			httpClient: http.DefaultClient,
+		},
+		T: t,
+	}
+	a := &Alerts{client: client}
+
+	id := "1234"
+	alert := &Alert{ID: &id}
+	if err := a.Trash(alert); err != nil {
+		t.Fatal(err)
+	}
+	if req := client.last(); req.Method != "DELETE" || req.URL.Query().Get("skipTrash") != "" {
+		t.Errorf("expected a plain DELETE, got %s %s", req.Method, req.URL)
+	}
+	if alert.ID == nil || *alert.ID != "1234" {
+		t.Error("expected alert ID to be kept after moving to the trash")
+	}
+
+	if _, err := a.FindDeleted(nil); err != nil {
+		t.Fatal(err)
+	}
+	if path := client.last().URL.Path; path != "/api/v2/search/alert/deleted" {
+		t.Errorf("expected a search of deleted alerts, got %s", path)
+	}
+
+	if err := a.Undelete(alert); err != nil {
+		t.Fatal(err)
+	}
+	if req := client.last(); req.Method != "POST" || req.URL.Path != "/api/v2/alert/1234/undelete" {
+		t.Errorf("unexpected undelete request %s %s", req.Method, req.URL)
+	}
+
+	if err := a.DeletePermanently(alert); err != nil {
+		t.Fatal(err)
+	}
+	if req := client.last(); req.Method != "DELETE" || req.URL.Query().Get("skipTrash") != "true" {
+		t.Errorf("expected DELETE with skipTrash=true, got %s %s", req.Method, req.URL)
+	}
+	if alert.ID != nil {
+		t.Error("expected alert ID to be reset after permanent deletion")
+	}
+	if err := a.DeletePermanently(alert); err == nil {
+		t.Error("expected permanent deletion to error with no ID")
+	}
+}
+
+func TestDashboards_Trash(t *testing.T) {
+	baseurl, _ := url.Parse("http://testing.wavefront.com")
+	client := &MockTrashClient{
+		Client: Client{
+			Config:     &Config{Token: "1234-5678-9977"},
+			BaseURL:    baseurl,
+			httpClient: http.DefaultClient,
+		},
+		T: t,
+	}
+	d := &Dashboards{client: client}
+
+	dashboard := &Dashboard{ID: "my-dash"}
+	if err := d.Undelete(dashboard); err != nil {
+		t.Fatal(err)
+	}
+	if req := client.last(); req.Method != "POST" || req.URL.Path != "/api/v2/dashboard/my-dash/undelete" {
+		t.Errorf("unexpected undelete request %s %s", req.Method, req.URL)
+	}
+
+	dashboard = &Dashboard{ID: "my-dash"}
+	if err := d.DeletePermanently(dashboard); err != nil {
+		t.Fatal(err)
+	}
+	if req := client.last(); req.URL.Path != "/api/v2/dashboard/my-dash" || req.URL.Query().Get("skipTrash") != "true" {
+		t.Errorf("expected DELETE with skipTrash=true, got %s %s", req.Method, req.URL)
+	}
+	if dashboard.ID != "" {
+		t.Error("expected dashboard ID to be reset after permanent deletion")
+	}
+}
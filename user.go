@@ -0,0 +1,249 @@
+package wavefront
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+)
+
+// User represents a single Wavefront User
+type User struct {
+	// ID is the identifier of the User, their email address
+	ID *string `json:"identifier,omitempty"`
+
+	// Permissions are the permissions granted directly to the User,
+	// e.g. alerts_management
+	Permissions []string `json:"groups"`
+
+	// UserGroups are the IDs of the user groups the User belongs to
+	UserGroups []string `json:"-"`
+
+	// Roles are the IDs of the roles assigned directly to the User
+	Roles []string `json:"-"`
+
+	// Customer is the Wavefront customer the User belongs to
+	Customer string `json:"customer,omitempty"`
+
+	SSOID               string `json:"ssoId,omitempty"`
+	LastSuccessfulLogin int64  `json:"lastSuccessfulLogin,omitempty"`
+}
+
+// Users is used to perform user-related operations against the Wavefront API
+type Users struct {
+	// client is the Wavefront client used to perform user-related operations
+	client Wavefronter
+}
+
+const baseUserPath = "/api/v2/user"
+
+// UnmarshalJSON is a custom JSON unmarshaller for a User, used in order to
+// reduce the user groups and roles Wavefront returns to their IDs
+func (u *User) UnmarshalJSON(b []byte) error {
+	type user User
+	temp := struct {
+		UserGroups json.RawMessage `json:"userGroups"`
+		Roles      json.RawMessage `json:"roles"`
+		*user
+	}{
+		user: (*user)(u),
+	}
+	if err := json.Unmarshal(b, &temp); err != nil {
+		return err
+	}
+
+	var err error
+	if u.UserGroups, err = idList(temp.UserGroups); err != nil {
+		return err
+	}
+	u.Roles, err = idList(temp.Roles)
+	return err
+}
+
+// MarshalJSON is a custom JSON marshaller for a User. Wavefront accepts a
+// different shape of user than it returns, so only the fields that can be
+// set are sent.
+func (u *User) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&struct {
+		ID          *string  `json:"identifier,omitempty"`
+		Permissions []string `json:"groups"`
+		UserGroups  []string `json:"userGroups"`
+		Roles       []string `json:"roles"`
+	}{
+		ID:          u.ID,
+		Permissions: u.Permissions,
+		UserGroups:  u.UserGroups,
+		Roles:       u.Roles,
+	})
+}
+
+// Users is used to return a client for user-related operations
+func (c *Client) Users() *Users {
+	return &Users{client: c}
+}
+
+// Get is used to retrieve an existing User by ID.
+// The ID field must be provided
+func (u Users) Get(user *User) error {
+	return u.GetContext(context.Background(), user)
+}
+
+// GetContext is like Get but carries the given context through the request.
+func (u Users) GetContext(ctx context.Context, user *User) error {
+	if user.ID == nil || *user.ID == "" {
+		return fmt.Errorf("User id field is not set")
+	}
+
+	return u.crudUser(ctx, "GET", fmt.Sprintf("%s/%s", baseUserPath, url.PathEscape(*user.ID)), user)
+}
+
+// Find returns all users filtered by the given search conditions.
+// If filter is nil, all users are returned.
+func (u Users) Find(filter []*SearchCondition) ([]*User, error) {
+	return u.FindContext(context.Background(), filter)
+}
+
+// FindContext is like Find but carries the given context through every page
+// of the search. Pagination stops as soon as the context is done.
+func (u Users) FindContext(ctx context.Context, filter []*SearchCondition) ([]*User, error) {
+	search := &Search{
+		client: u.client,
+		Type:   "user",
+		Params: &SearchParams{
+			Conditions: filter,
+		},
+	}
+
+	var results []*User
+	moreItems := true
+	for moreItems == true {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		resp, err := search.ExecuteContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		var tmpres []*User
+		err = json.Unmarshal(resp.Response.Items, &tmpres)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, tmpres...)
+		moreItems = resp.Response.MoreItems
+		search.Params.Offset = resp.NextOffset
+	}
+
+	return results, nil
+}
+
+// Invite is used to invite a new User to Wavefront, who is sent an email.
+// The ID field must be set to their email address, and the user is updated
+// from the response.
+func (u Users) Invite(user *User) error {
+	return u.InviteContext(context.Background(), user)
+}
+
+// InviteContext is like Invite but carries the given context through the request.
+func (u Users) InviteContext(ctx context.Context, user *User) error {
+	if user.ID == nil || *user.ID == "" {
+		return fmt.Errorf("User id field is not set")
+	}
+
+	payload, err := json.Marshal([]*User{user})
+	if err != nil {
+		return err
+	}
+	req, err := newRequestWithContext(ctx, u.client, "POST", baseUserPath+"/invite", nil, payload)
+	if err != nil {
+		return err
+	}
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Close()
+
+	body, err := ioutil.ReadAll(resp)
+	if err != nil {
+		return err
+	}
+
+	temp := struct {
+		Response []*User `json:"response"`
+	}{}
+	if err := json.Unmarshal(body, &temp); err != nil {
+		return err
+	}
+	if len(temp.Response) > 0 {
+		*user = *temp.Response[0]
+	}
+	return nil
+}
+
+// Update is used to update the permissions, user groups and roles of an
+// existing User. The ID field of the user must be populated
+func (u Users) Update(user *User) error {
+	return u.UpdateContext(context.Background(), user)
+}
+
+// UpdateContext is like Update but carries the given context through the request.
+func (u Users) UpdateContext(ctx context.Context, user *User) error {
+	if user.ID == nil {
+		return fmt.Errorf("user id field not set")
+	}
+
+	return u.crudUser(ctx, "PUT", fmt.Sprintf("%s/%s", baseUserPath, url.PathEscape(*user.ID)), user)
+}
+
+// Delete is used to delete an existing User.
+// The ID field of the user must be populated
+func (u Users) Delete(user *User) error {
+	return u.DeleteContext(context.Background(), user)
+}
+
+// DeleteContext is like Delete but carries the given context through the request.
+func (u Users) DeleteContext(ctx context.Context, user *User) error {
+	if user.ID == nil {
+		return fmt.Errorf("user id field not set")
+	}
+
+	err := u.crudUser(ctx, "DELETE", fmt.Sprintf("%s/%s", baseUserPath, url.PathEscape(*user.ID)), user)
+	if err != nil {
+		return err
+	}
+
+	//reset the ID field so deletion is not attempted again
+	user.ID = nil
+	return nil
+}
+
+func (u Users) crudUser(ctx context.Context, method, path string, user *User) error {
+	payload, err := json.Marshal(user)
+	if err != nil {
+		return err
+	}
+	req, err := newRequestWithContext(ctx, u.client, method, path, nil, payload)
+	if err != nil {
+		return err
+	}
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Close()
+
+	body, err := ioutil.ReadAll(resp)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(body, &struct {
+		Response *User `json:"response"`
+	}{
+		Response: user,
+	})
+}
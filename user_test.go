@@ -0,0 +1,155 @@
+package wavefront
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+type MockUserClient struct {
+	Client
+	InvokedCount int
+	T            *testing.T
+}
+
+type MockCrudUserClient struct {
+	Client
+	method string
+	path   string
+	body   []byte
+	T      *testing.T
+}
+
+func (m *MockUserClient) Do(req *http.Request) (io.ReadCloser, error) {
+	response, err := ioutil.ReadFile(fmt.Sprintf("./fixtures/paginated-user-%d.json", m.InvokedCount))
+	if err != nil {
+		m.T.Fatal(err)
+	}
+	if req.URL.Path != "/api/v2/search/user" {
+		m.T.Errorf("search path expected /api/v2/search/user, got %s", req.URL.Path)
+	}
+	body, _ := ioutil.ReadAll(req.Body)
+	search := SearchParams{}
+	err = json.Unmarshal(body, &search)
+	if err != nil {
+		m.T.Fatal(err)
+	}
+	if search.Offset != search.Limit*m.InvokedCount {
+		m.T.Errorf("offset, expected %d, got %d", search.Limit*m.InvokedCount, search.Offset)
+	}
+	m.InvokedCount++
+	return ioutil.NopCloser(bytes.NewReader(response)), nil
+}
+
+func TestUsers_PaginatedFind(t *testing.T) {
+	baseurl, _ := url.Parse("http://testing.wavefront.com")
+	u := &Users{
+		client: &MockUserClient{
+			Client: Client{
+				Config:     &Config{Token: "1234-5678-9977"},
+				BaseURL:    baseurl,
+				httpClient: http.DefaultClient,
+				debug:      true,
+			},
+			T: t,
+		},
+	}
+	users, err := u.Find(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	invoked := ((u.client).(*MockUserClient)).InvokedCount
+	if invoked != 2 {
+		t.Errorf("paginated search, expected 2, got %d", invoked)
+	}
+
+	if len(users) != 2 || *users[1].ID != "user1@example.com" {
+		t.Fatalf("unexpected users: %+v", users)
+	}
+
+	if len(users[1].UserGroups) != 2 || users[1].UserGroups[1] != "group-2" {
+		t.Errorf("user groups, expected [group-1 group-2], got %v", users[1].UserGroups)
+	}
+}
+
+func (m *MockCrudUserClient) Do(req *http.Request) (io.ReadCloser, error) {
+	fixture := "./fixtures/create-user-response.json"
+	if req.URL.Path == "/api/v2/user/invite" {
+		fixture = "./fixtures/invite-user-response.json"
+	}
+	response, err := ioutil.ReadFile(fixture)
+	if err != nil {
+		m.T.Fatal(err)
+	}
+	if req.Method != m.method {
+		m.T.Errorf("request method expected '%s' got '%s'", m.method, req.Method)
+	}
+	m.path = req.URL.EscapedPath()
+	m.body, _ = ioutil.ReadAll(req.Body)
+	return ioutil.NopCloser(bytes.NewReader(response)), nil
+}
+
+func TestUsers_InviteUpdateDeleteUser(t *testing.T) {
+	baseurl, _ := url.Parse("http://testing.wavefront.com")
+	client := &MockCrudUserClient{
+		Client: Client{
+			Config:     &Config{Token: "1234-5678-9977"},
+			BaseURL:    baseurl,
+			httpClient: http.DefaultClient,
+			debug:      true,
+		},
+		method: "POST",
+		T:      t,
+	}
+	u := &Users{client: client}
+
+	if err := u.Invite(&User{}); err == nil {
+		t.Errorf("expected user invite to error with no ID")
+	}
+
+	id := "someone@example.com"
+	user := User{
+		ID:          &id,
+		Permissions: []string{"alerts_management"},
+		UserGroups:  []string{"group-1"},
+	}
+	if err := u.Invite(&user); err != nil {
+		t.Fatal(err)
+	}
+	var invited []map[string]interface{}
+	if err := json.Unmarshal(client.body, &invited); err != nil {
+		t.Fatal(err)
+	}
+	if len(invited) != 1 || invited[0]["identifier"] != id {
+		t.Errorf("expected an invite for %s, got %s", id, client.body)
+	}
+	if user.Customer != "example" || len(user.UserGroups) != 1 {
+		t.Errorf("expected user to be updated from the response, got %+v", user)
+	}
+
+	client.method = "PUT"
+	user.Permissions = append(user.Permissions, "dashboard_management")
+	if err := u.Update(&user); err != nil {
+		t.Fatal(err)
+	}
+	if client.path != "/api/v2/user/someone@example.com" {
+		t.Errorf("unexpected path %s", client.path)
+	}
+	if len(user.Roles) != 1 || user.Roles[0] != "role-1" {
+		t.Errorf("roles expected [role-1], got %v", user.Roles)
+	}
+
+	client.method = "DELETE"
+	if err := u.Delete(&user); err != nil {
+		t.Error(err)
+	}
+
+	if user.ID != nil {
+		t.Error("expected user ID to be reset after deletion")
+	}
+}
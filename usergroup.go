@@ -0,0 +1,302 @@
+package wavefront
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+)
+
+// UserGroup represents a single Wavefront User Group
+type UserGroup struct {
+	// ID is the Wavefront-assigned ID of an existing User Group
+	ID *string `json:"id,omitempty"`
+
+	// Name is the name given to the User Group
+	Name string `json:"name"`
+
+	// Description is a description of the User Group
+	Description string `json:"description,omitempty"`
+
+	// Users are the IDs of the users in the User Group. They are changed
+	// with AddUsers and RemoveUsers.
+	Users []string `json:"users,omitempty"`
+
+	// Roles are the IDs of the roles assigned to the User Group
+	Roles []string `json:"-"`
+
+	UserCount          int    `json:"userCount,omitempty"`
+	Customer           string `json:"customer,omitempty"`
+	CreatedEpochMillis int64  `json:"createdEpochMillis,omitempty"`
+}
+
+// UserGroups is used to perform user group-related operations against the
+// Wavefront API
+type UserGroups struct {
+	// client is the Wavefront client used to perform user group-related operations
+	client Wavefronter
+}
+
+const baseUserGroupPath = "/api/v2/usergroup"
+
+// UnmarshalJSON is a custom JSON unmarshaller for a UserGroup, used in order
+// to reduce the roles Wavefront returns to their IDs
+func (g *UserGroup) UnmarshalJSON(b []byte) error {
+	type userGroup UserGroup
+	temp := struct {
+		Roles json.RawMessage `json:"roles"`
+		*userGroup
+	}{
+		userGroup: (*userGroup)(g),
+	}
+	if err := json.Unmarshal(b, &temp); err != nil {
+		return err
+	}
+
+	var err error
+	g.Roles, err = idList(temp.Roles)
+	return err
+}
+
+// MarshalJSON is a custom JSON marshaller for a UserGroup. Wavefront accepts a
+// different shape of user group than it returns, so only the fields that can
+// be set are sent.
+func (g *UserGroup) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&struct {
+		ID          *string  `json:"id,omitempty"`
+		Name        string   `json:"name"`
+		Description string   `json:"description,omitempty"`
+		Roles       []string `json:"roleIDs"`
+	}{
+		ID:          g.ID,
+		Name:        g.Name,
+		Description: g.Description,
+		Roles:       g.Roles,
+	})
+}
+
+// UserGroups is used to return a client for user group-related operations
+func (c *Client) UserGroups() *UserGroups {
+	return &UserGroups{client: c}
+}
+
+// Get is used to retrieve an existing UserGroup by ID.
+// The ID field must be provided
+func (u UserGroups) Get(group *UserGroup) error {
+	return u.GetContext(context.Background(), group)
+}
+
+// GetContext is like Get but carries the given context through the request.
+func (u UserGroups) GetContext(ctx context.Context, group *UserGroup) error {
+	if group.ID == nil || *group.ID == "" {
+		return fmt.Errorf("UserGroup id field is not set")
+	}
+
+	return u.crudUserGroup(ctx, "GET", fmt.Sprintf("%s/%s", baseUserGroupPath, *group.ID), group)
+}
+
+// Find returns all user groups filtered by the given search conditions.
+// If filter is nil, all user groups are returned.
+func (u UserGroups) Find(filter []*SearchCondition) ([]*UserGroup, error) {
+	return u.FindContext(context.Background(), filter)
+}
+
+// FindContext is like Find but carries the given context through every page
+// of the search. Pagination stops as soon as the context is done.
+func (u UserGroups) FindContext(ctx context.Context, filter []*SearchCondition) ([]*UserGroup, error) {
+	search := &Search{
+		client: u.client,
+		Type:   "usergroup",
+		Params: &SearchParams{
+			Conditions: filter,
+		},
+	}
+
+	var results []*UserGroup
+	moreItems := true
+	for moreItems == true {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		resp, err := search.ExecuteContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		var tmpres []*UserGroup
+		err = json.Unmarshal(resp.Response.Items, &tmpres)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, tmpres...)
+		moreItems = resp.Response.MoreItems
+		search.Params.Offset = resp.NextOffset
+	}
+
+	return results, nil
+}
+
+// Create is used to create a UserGroup in Wavefront.
+// If successful, the ID field of the user group will be populated.
+func (u UserGroups) Create(group *UserGroup) error {
+	return u.CreateContext(context.Background(), group)
+}
+
+// CreateContext is like Create but carries the given context through the request.
+func (u UserGroups) CreateContext(ctx context.Context, group *UserGroup) error {
+	return u.crudUserGroup(ctx, "POST", baseUserGroupPath, group)
+}
+
+// Update is used to update the name, description and roles of an existing
+// UserGroup. The ID field of the user group must be populated
+func (u UserGroups) Update(group *UserGroup) error {
+	return u.UpdateContext(context.Background(), group)
+}
+
+// UpdateContext is like Update but carries the given context through the request.
+func (u UserGroups) UpdateContext(ctx context.Context, group *UserGroup) error {
+	if group.ID == nil {
+		return fmt.Errorf("user group id field not set")
+	}
+
+	return u.crudUserGroup(ctx, "PUT", fmt.Sprintf("%s/%s", baseUserGroupPath, *group.ID), group)
+}
+
+// Delete is used to delete an existing UserGroup.
+// The ID field of the user group must be populated
+func (u UserGroups) Delete(group *UserGroup) error {
+	return u.DeleteContext(context.Background(), group)
+}
+
+// DeleteContext is like Delete but carries the given context through the request.
+func (u UserGroups) DeleteContext(ctx context.Context, group *UserGroup) error {
+	if group.ID == nil {
+		return fmt.Errorf("user group id field not set")
+	}
+
+	err := u.crudUserGroup(ctx, "DELETE", fmt.Sprintf("%s/%s", baseUserGroupPath, *group.ID), group)
+	if err != nil {
+		return err
+	}
+
+	//reset the ID field so deletion is not attempted again
+	group.ID = nil
+	return nil
+}
+
+// AddUsers is used to add the users with the given IDs to an existing
+// UserGroup, which is updated from the response.
+// The ID field of the user group must be populated
+func (u UserGroups) AddUsers(group *UserGroup, userIDs ...string) error {
+	return u.AddUsersContext(context.Background(), group, userIDs...)
+}
+
+// AddUsersContext is like AddUsers but carries the given context through the request.
+func (u UserGroups) AddUsersContext(ctx context.Context, group *UserGroup, userIDs ...string) error {
+	return u.userGroupAction(ctx, "addUsers", userIDs, group)
+}
+
+// RemoveUsers is used to remove the users with the given IDs from an existing
+// UserGroup, which is updated from the response.
+// The ID field of the user group must be populated
+func (u UserGroups) RemoveUsers(group *UserGroup, userIDs ...string) error {
+	return u.RemoveUsersContext(context.Background(), group, userIDs...)
+}
+
+// RemoveUsersContext is like RemoveUsers but carries the given context through the request.
+func (u UserGroups) RemoveUsersContext(ctx context.Context, group *UserGroup, userIDs ...string) error {
+	return u.userGroupAction(ctx, "removeUsers", userIDs, group)
+}
+
+// AddRoles is used to assign the roles with the given IDs to an existing
+// UserGroup, which is updated from the response.
+// The ID field of the user group must be populated
+func (u UserGroups) AddRoles(group *UserGroup, roleIDs ...string) error {
+	return u.AddRolesContext(context.Background(), group, roleIDs...)
+}
+
+// AddRolesContext is like AddRoles but carries the given context through the request.
+func (u UserGroups) AddRolesContext(ctx context.Context, group *UserGroup, roleIDs ...string) error {
+	return u.userGroupAction(ctx, "addRoles", roleIDs, group)
+}
+
+// RemoveRoles is used to unassign the roles with the given IDs from an
+// existing UserGroup, which is updated from the response.
+// The ID field of the user group must be populated
+func (u UserGroups) RemoveRoles(group *UserGroup, roleIDs ...string) error {
+	return u.RemoveRolesContext(context.Background(), group, roleIDs...)
+}
+
+// RemoveRolesContext is like RemoveRoles but carries the given context through the request.
+func (u UserGroups) RemoveRolesContext(ctx context.Context, group *UserGroup, roleIDs ...string) error {
+	return u.userGroupAction(ctx, "removeRoles", roleIDs, group)
+}
+
+// userGroupAction POSTs a list of IDs to an action endpoint of a UserGroup,
+// such as addUsers, and replaces group with the UserGroup in the response
+func (u UserGroups) userGroupAction(ctx context.Context, action string, ids []string, group *UserGroup) error {
+	if group.ID == nil {
+		return fmt.Errorf("user group id field not set")
+	}
+	if ids == nil {
+		ids = []string{}
+	}
+
+	payload, err := json.Marshal(ids)
+	if err != nil {
+		return err
+	}
+	req, err := newRequestWithContext(ctx, u.client, "POST", fmt.Sprintf("%s/%s/%s", baseUserGroupPath, *group.ID, action), nil, payload)
+	if err != nil {
+		return err
+	}
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Close()
+
+	body, err := ioutil.ReadAll(resp)
+	if err != nil {
+		return err
+	}
+
+	temp := struct {
+		Response *UserGroup `json:"response"`
+	}{}
+	if err := json.Unmarshal(body, &temp); err != nil {
+		return err
+	}
+	if temp.Response != nil {
+		*group = *temp.Response
+	}
+	return nil
+}
+
+func (u UserGroups) crudUserGroup(ctx context.Context, method, path string, group *UserGroup) error {
+	payload, err := json.Marshal(group)
+	if err != nil {
+		return err
+	}
+	req, err := newRequestWithContext(ctx, u.client, method, path, nil, payload)
+	if err != nil {
+		return err
+	}
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Close()
+
+	body, err := ioutil.ReadAll(resp)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(body, &struct {
+		Response *UserGroup `json:"response"`
+	}{
+		Response: group,
+	})
+}
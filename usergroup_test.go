@@ -0,0 +1,128 @@
+package wavefront
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+type MockCrudUserGroupClient struct {
+	Client
+	method string
+	path   string
+	body   []byte
+	T      *testing.T
+}
+
+func (m *MockCrudUserGroupClient) Do(req *http.Request) (io.ReadCloser, error) {
+	response, err := ioutil.ReadFile("./fixtures/create-usergroup-response.json")
+	if err != nil {
+		m.T.Fatal(err)
+	}
+	if req.Method != m.method {
+		m.T.Errorf("request method expected '%s' got '%s'", m.method, req.Method)
+	}
+	m.path = req.URL.Path
+	m.body, _ = ioutil.ReadAll(req.Body)
+	return ioutil.NopCloser(bytes.NewReader(response)), nil
+}
+
+func TestUserGroups_CreateUpdateDeleteUserGroup(t *testing.T) {
+	baseurl, _ := url.Parse("http://testing.wavefront.com")
+	client := &MockCrudUserGroupClient{
+		Client: Client{
+			Config:     &Config{Token: "1234-5678-9977"},
+			BaseURL:    baseurl,
+			httpClient: http.DefaultClient,
+			debug:      true,
+		},
+		method: "PUT",
+		T:      t,
+	}
+	u := &UserGroups{client: client}
+
+	group := UserGroup{
+		Name:        "Operators",
+		Description: "On-call operators",
+		Roles:       []string{"role-1"},
+	}
+
+	if err := u.Update(&group); err == nil {
+		t.Errorf("expected user group update to error with no ID")
+	}
+
+	client.method = "POST"
+	if err := u.Create(&group); err != nil {
+		t.Fatal(err)
+	}
+	sent := map[string]interface{}{}
+	if err := json.Unmarshal(client.body, &sent); err != nil {
+		t.Fatal(err)
+	}
+	if roles, ok := sent["roleIDs"].([]interface{}); !ok || len(roles) != 1 {
+		t.Errorf("expected roleIDs to be sent, got %s", client.body)
+	}
+	if *group.ID != "group-2" {
+		t.Errorf("user group ID expected group-2, got %s", *group.ID)
+	}
+	if len(group.Roles) != 1 || group.Roles[0] != "role-1" {
+		t.Errorf("roles expected [role-1], got %v", group.Roles)
+	}
+
+	client.method = "PUT"
+	if err := u.Update(&group); err != nil {
+		t.Error(err)
+	}
+
+	client.method = "DELETE"
+	if err := u.Delete(&group); err != nil {
+		t.Error(err)
+	}
+
+	if group.ID != nil {
+		t.Error("expected user group ID to be reset after deletion")
+	}
+}
+
+func TestUserGroups_Members(t *testing.T) {
+	client := &MockCrudUserGroupClient{
+		Client: Client{
+			Config:     &Config{Token: "1234-5678-9977"},
+			BaseURL:    &url.URL{Scheme: "http", Host: "testing.wavefront.com"},
+			httpClient: http.DefaultClient,
+		},
+		method: "POST",
+		T:      t,
+	}
+	u := &UserGroups{client: client}
+
+	if err := u.AddUsers(&UserGroup{}, "someone@example.com"); err == nil {
+		t.Errorf("expected adding users to error with no ID")
+	}
+
+	for action, f := range map[string]func(*UserGroup, ...string) error{
+		"addUsers":    u.AddUsers,
+		"removeUsers": u.RemoveUsers,
+		"addRoles":    u.AddRoles,
+		"removeRoles": u.RemoveRoles,
+	} {
+		id := "group-2"
+		group := &UserGroup{ID: &id}
+		if err := f(group, "a", "b"); err != nil {
+			t.Fatal(err)
+		}
+		if client.path != "/api/v2/usergroup/group-2/"+action {
+			t.Errorf("%s: unexpected path %s", action, client.path)
+		}
+		if string(client.body) != `["a","b"]` {
+			t.Errorf("%s: unexpected body %s", action, client.body)
+		}
+		if group.Name != "Operators" || len(group.Users) != 1 {
+			t.Errorf("%s: expected user group to be updated from the response, got %+v", action, group)
+		}
+	}
+}
@@ -0,0 +1,1115 @@
+// Package wavefronttest provides an in-memory fake of the Wavefront API, for
+// end-to-end testing of code built on the wavefront package without network
+// access to a real Wavefront cluster.
+package wavefronttest
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	wavefront "github.com/spaceapegames/go-wavefront"
+)
+
+// Token is the API token accepted by a Server
+const Token = "wavefronttest-token"
+
+// entityTypes are the /api/v2 entity endpoints served, mapped to whether
+// deleting an entity moves it to the trash (rather than removing it outright)
+var entityTypes = map[string]bool{
+	"alert":             true,
+	"cloudintegration":  true,
+	"dashboard":         true,
+	"derivedmetric":     true,
+	"event":             false,
+	"extlink":           false,
+	"maintenancewindow": false,
+	"notificant":        false,
+	"proxy":             false,
+	"role":              false,
+	"serviceaccount":    false,
+	"source":            false,
+	"user":              false,
+	"usergroup":         false,
+}
+
+// historyTypes are the entity types whose version history is kept
+var historyTypes = map[string]bool{
+	"alert":     true,
+	"dashboard": true,
+}
+
+// taggableTypes are the entity types with tag endpoints
+var taggableTypes = map[string]bool{
+	"alert":         true,
+	"dashboard":     true,
+	"derivedmetric": true,
+	"event":         true,
+	"source":        true,
+}
+
+// Server is a fake Wavefront API server. It supports CRUD operations,
+// search and seeded chart queries against an in-memory store.
+type Server struct {
+	// Server is the underlying TLS test server
+	*httptest.Server
+
+	mu          sync.Mutex
+	collections map[string]*collection
+	queries     map[string]*wavefront.QueryResponse
+	nextID      int
+
+	// account holds the API tokens of the account the clients authenticate as
+	account map[string]interface{}
+}
+
+// collection holds the entities of one type, in creation order
+type collection struct {
+	ids     []string
+	live    map[string]map[string]interface{}
+	deleted map[string]map[string]interface{}
+	history map[string][]*revision
+}
+
+// revision is one version of an entity, oldest first
+type revision struct {
+	entry wavefront.HistoryEntry
+	obj   map[string]interface{}
+}
+
+// NewServer starts and returns a new Server. It should be closed with Close
+// when no longer required.
+func NewServer() *Server {
+	s := &Server{
+		collections: map[string]*collection{},
+		queries:     map[string]*wavefront.QueryResponse{},
+		nextID:      1,
+		account:     map[string]interface{}{"tokens": []interface{}{}},
+	}
+	for t := range entityTypes {
+		s.collections[t] = &collection{
+			live:    map[string]map[string]interface{}{},
+			deleted: map[string]map[string]interface{}{},
+			history: map[string][]*revision{},
+		}
+	}
+	s.Server = httptest.NewTLSServer(s)
+	return s
+}
+
+// ClientConfig returns a wavefront.Config for a client of the Server. The
+// server's certificate is trusted, so TLS verification remains enabled.
+func (s *Server) ClientConfig() *wavefront.Config {
+	pool := x509.NewCertPool()
+	pool.AddCert(s.Certificate())
+	return &wavefront.Config{
+		Address:         strings.TrimPrefix(s.URL, "https://"),
+		Token:           Token,
+		TLSClientConfig: &tls.Config{RootCAs: pool},
+	}
+}
+
+// SetQueryResponse seeds the response returned by the chart API for the given
+// query string. Queries that have not been seeded return no time series.
+func (s *Server) SetQueryResponse(query string, resp *wavefront.QueryResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.queries[query] = resp
+}
+
+// Seed stores entity, which may be any value that marshals to a Wavefront
+// entity (e.g. a *wavefront.Alert), under the given type and returns its ID.
+// An ID is assigned if the entity does not have one.
+func (s *Server) Seed(entityType string, entity interface{}) (string, error) {
+	b, err := json.Marshal(entity)
+	if err != nil {
+		return "", err
+	}
+	obj := map[string]interface{}{}
+	if err := json.Unmarshal(b, &obj); err != nil {
+		return "", err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.collections[entityType]
+	if !ok {
+		return "", fmt.Errorf("unsupported entity type %s, expected one of %s",
+			entityType, strings.Join(sortedKeys(entityTypes), ", "))
+	}
+	return s.create(entityType, c, obj)
+}
+
+// ServeHTTP routes a request to the fake API
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "Bearer "+Token {
+		writeError(w, http.StatusUnauthorized, "invalid token")
+		return
+	}
+	if !strings.HasPrefix(r.URL.Path, "/api/v2/") {
+		writeError(w, http.StatusNotFound, "unknown path "+r.URL.Path)
+		return
+	}
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v2/"), "/"), "/")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case parts[0] == "search" && len(parts) == 2:
+		s.search(w, r, parts[1], false)
+	case parts[0] == "search" && len(parts) == 3 && parts[2] == "deleted":
+		s.search(w, r, parts[1], true)
+	case parts[0] == "chart" && len(parts) == 2 && parts[1] == "api" && r.Method == "GET":
+		s.query(w, r)
+	case parts[0] == "user" && len(parts) == 2 && parts[1] == "invite" && r.Method == "POST":
+		s.inviteUsers(w, r)
+	case parts[0] == "account" && len(parts) >= 2 && parts[1] == "serviceaccount":
+		s.entity(w, r, "serviceaccount", s.collections["serviceaccount"], parts[2:])
+	case parts[0] == "account" && len(parts) == 2 && r.Method == "DELETE":
+		s.deleteAccount(w, parts[1])
+	case (parts[0] == "alert" || parts[0] == "dashboard") && len(parts) >= 2 && parts[1] == "acl":
+		s.acl(w, r, parts[0], parts[2:])
+	case parts[0] == "apitoken":
+		s.apiTokens(w, r, parts[1:])
+	case parts[0] == "role" && len(parts) == 3 && (parts[1] == "grant" || parts[1] == "revoke") && r.Method == "POST":
+		s.rolePermission(w, r, parts[1], parts[2])
+	default:
+		if c, ok := s.collections[parts[0]]; ok {
+			s.entity(w, r, parts[0], c, parts[1:])
+			return
+		}
+		writeError(w, http.StatusNotFound, "unknown path "+r.URL.Path)
+	}
+}
+
+// entity handles the CRUD endpoints of a single entity type
+func (s *Server) entity(w http.ResponseWriter, r *http.Request, entityType string, c *collection, parts []string) {
+	if len(parts) == 0 && r.Method == "GET" && entityType == "source" {
+		s.listSources(w, r, c)
+		return
+	}
+	if len(parts) == 0 && r.Method == "GET" && entityType == "proxy" {
+		s.listProxies(w, r, c)
+		return
+	}
+	if len(parts) == 0 {
+		if r.Method != "POST" {
+			writeError(w, http.StatusMethodNotAllowed, r.Method+" not allowed")
+			return
+		}
+		obj, err := readObject(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		id, err := s.create(entityType, c, obj)
+		if err != nil {
+			writeError(w, http.StatusConflict, err.Error())
+			return
+		}
+		writeResponse(w, c.live[id])
+		return
+	}
+
+	id := parts[0]
+	obj, live := c.live[id]
+	if !live {
+		obj = c.deleted[id]
+	}
+	if obj == nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("%s %s does not exist", entityType, id))
+		return
+	}
+
+	switch {
+	case len(parts) == 1 && r.Method == "GET":
+		writeResponse(w, obj)
+
+	case len(parts) == 1 && r.Method == "PUT":
+		if !live {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("%s %s is deleted", entityType, id))
+			return
+		}
+		updated, err := readObject(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if entityType == "proxy" {
+			// only the name of a proxy can be changed
+			name := updated["name"]
+			updated = copyObject(obj)
+			updated["name"] = name
+		}
+		normalise(entityType, updated)
+		if entityType == "user" || entityType == "serviceaccount" {
+			updated["identifier"] = id
+		}
+		if acl, ok := obj["acl"]; ok {
+			// ACLs are only changed through the acl endpoints
+			updated["acl"] = acl
+		}
+		if entityType == "serviceaccount" {
+			// tokens are only changed through the apitoken endpoints
+			updated["tokens"] = obj["tokens"]
+		}
+		if entityType == "usergroup" {
+			// membership is only changed through addUsers and removeUsers
+			updated["users"] = obj["users"]
+			updated["userCount"] = obj["userCount"]
+		}
+		updated["id"] = id
+		updated["updatedEpochMillis"] = nowMillis()
+		c.live[id] = updated
+		c.record(entityType, id, updated, "Updated")
+		writeResponse(w, updated)
+
+	case len(parts) == 1 && r.Method == "DELETE":
+		if live && entityTypes[entityType] && r.URL.Query().Get("skipTrash") != "true" {
+			// the first delete moves the entity to the trash
+			delete(c.live, id)
+			obj["deleted"] = true
+			c.deleted[id] = obj
+		} else {
+			delete(c.live, id)
+			delete(c.deleted, id)
+			delete(c.history, id)
+			c.remove(id)
+		}
+		writeResponse(w, obj)
+
+	case len(parts) == 2 && parts[1] == "close" && r.Method == "POST" && entityType == "event":
+		obj["endTime"] = nowMillis()
+		writeResponse(w, obj)
+
+	case len(parts) == 2 && parts[1] == "undelete" && r.Method == "POST" && entityTypes[entityType]:
+		if live {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("%s %s is not deleted", entityType, id))
+			return
+		}
+		delete(c.deleted, id)
+		delete(obj, "deleted")
+		c.live[id] = obj
+		writeResponse(w, obj)
+
+	case len(parts) == 2 && parts[1] == "history" && r.Method == "GET" && historyTypes[entityType]:
+		s.listHistory(w, r, c.history[id])
+
+	case len(parts) == 2 && isVersion(parts[1]) && r.Method == "GET" && historyTypes[entityType]:
+		rev := c.revision(id, parts[1])
+		if rev == nil {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("%s %s has no version %s", entityType, id, parts[1]))
+			return
+		}
+		writeResponse(w, rev.obj)
+
+	case len(parts) == 2 && (parts[1] == "enable" || parts[1] == "disable") && r.Method == "POST" && entityType == "cloudintegration":
+		obj["disabled"] = parts[1] == "disable"
+		writeResponse(w, obj)
+
+	case len(parts) == 2 && parts[1] == "description" && entityType == "source":
+		if r.Method == "DELETE" {
+			delete(obj, "description")
+			writeResponse(w, nil)
+			return
+		}
+		body, err := ioutil.ReadAll(r.Body)
+		var description string
+		if err == nil {
+			err = json.Unmarshal(body, &description)
+		}
+		if err != nil || r.Method != "POST" {
+			writeError(w, http.StatusBadRequest, "expected a JSON string to POST")
+			return
+		}
+		obj["description"] = description
+		writeResponse(w, nil)
+
+	case len(parts) >= 2 && parts[1] == "tag" && taggableTypes[entityType]:
+		s.tags(w, r, entityType, obj, parts[2:])
+
+	case len(parts) == 2 && r.Method == "POST" && entityType == "alert":
+		s.alertAction(w, r, parts[1], obj)
+
+	case len(parts) == 2 && (parts[1] == "activate" || parts[1] == "deactivate") && r.Method == "POST" && entityType == "serviceaccount":
+		obj["active"] = parts[1] == "activate"
+		writeResponse(w, obj)
+
+	case len(parts) == 2 && r.Method == "POST" && entityType == "usergroup":
+		s.userGroupAction(w, r, parts[1], obj)
+
+	default:
+		writeError(w, http.StatusNotFound, "unknown path "+r.URL.Path)
+	}
+}
+
+// listSources handles GET /source, which pages through sources with a
+// cursor: the ID of the first source of the next page
+func (s *Server) listSources(w http.ResponseWriter, r *http.Request, c *collection) {
+	cursor := r.URL.Query().Get("cursor")
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit <= 0 {
+		limit = 100
+	}
+
+	ids := append([]string(nil), c.ids...)
+	sort.Strings(ids)
+	start := sort.SearchStrings(ids, cursor)
+	items := []map[string]interface{}{}
+	next := ""
+	for _, id := range ids[start:] {
+		if len(items) == limit {
+			next = id
+			break
+		}
+		items = append(items, c.live[id])
+	}
+	writeResponse(w, map[string]interface{}{
+		"items":     items,
+		"cursor":    next,
+		"limit":     limit,
+		"moreItems": next != "",
+	})
+}
+
+// listProxies handles GET /proxy, which pages through proxies with an offset
+func (s *Server) listProxies(w http.ResponseWriter, r *http.Request, c *collection) {
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit <= 0 {
+		limit = 100
+	}
+
+	items := []map[string]interface{}{}
+	for i := offset; i < len(c.ids) && len(items) < limit; i++ {
+		items = append(items, c.live[c.ids[i]])
+	}
+	writeResponse(w, map[string]interface{}{
+		"items":     items,
+		"offset":    offset,
+		"limit":     limit,
+		"moreItems": offset+len(items) < len(c.ids),
+	})
+}
+
+// alertAction handles the snooze, unsnooze, install and uninstall endpoints
+// of an alert
+func (s *Server) alertAction(w http.ResponseWriter, r *http.Request, action string, obj map[string]interface{}) {
+	switch action {
+	case "snooze":
+		snoozed := int64(-1)
+		if v := r.URL.Query().Get("seconds"); v != "" {
+			seconds, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, "invalid seconds "+v)
+				return
+			}
+			snoozed = nowMillis() + seconds*1000
+		}
+		obj["snoozed"] = snoozed
+	case "unsnooze":
+		delete(obj, "snoozed")
+	case "install", "uninstall":
+		// system alerts are not modelled, so there is nothing to change
+	default:
+		writeError(w, http.StatusNotFound, "unknown path "+r.URL.Path)
+		return
+	}
+	writeResponse(w, obj)
+}
+
+// tags handles the /{type}/{id}/tag endpoints of an entity
+func (s *Server) tags(w http.ResponseWriter, r *http.Request, entityType string, obj map[string]interface{}, parts []string) {
+	// events have a plain list of tags, sources a map of each tag to true and
+	// other entities the customerTags of a tags object
+	var tags interface{}
+	m, _ := obj["tags"].(map[string]interface{})
+	if m == nil {
+		m = map[string]interface{}{}
+	}
+	switch entityType {
+	case "event":
+		tags = obj["tags"]
+	case "source":
+		var set []string
+		for tag, v := range m {
+			if v == true {
+				set = append(set, tag)
+			}
+		}
+		sort.Strings(set)
+		tags = addStrings(nil, set)
+	default:
+		tags = m["customerTags"]
+	}
+
+	var updated []interface{}
+	switch {
+	case len(parts) == 0 && r.Method == "GET":
+		writeResponse(w, addStrings(tags, nil))
+		return
+	case len(parts) == 0 && r.Method == "POST":
+		values, err := readStrings(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		updated = addStrings(nil, values)
+	case len(parts) == 1 && r.Method == "PUT":
+		updated = addStrings(tags, parts)
+	case len(parts) == 1 && r.Method == "DELETE":
+		updated = removeStrings(tags, parts)
+	default:
+		writeError(w, http.StatusNotFound, "unknown path "+r.URL.Path)
+		return
+	}
+
+	switch entityType {
+	case "event":
+		obj["tags"] = updated
+	case "source":
+		set := map[string]interface{}{}
+		for _, tag := range updated {
+			set[tag.(string)] = true
+		}
+		obj["tags"] = set
+	default:
+		m["customerTags"] = updated
+		obj["tags"] = m
+	}
+	obj["updatedEpochMillis"] = nowMillis()
+	writeResponse(w, nil)
+}
+
+// userGroupAction handles the addUsers, removeUsers, addRoles and
+// removeRoles endpoints of a user group
+func (s *Server) userGroupAction(w http.ResponseWriter, r *http.Request, action string, obj map[string]interface{}) {
+	ids, err := readStrings(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	switch action {
+	case "addUsers":
+		obj["users"] = addStrings(obj["users"], ids)
+	case "removeUsers":
+		obj["users"] = removeStrings(obj["users"], ids)
+	case "addRoles":
+		obj["roles"] = addStrings(obj["roles"], ids)
+	case "removeRoles":
+		obj["roles"] = removeStrings(obj["roles"], ids)
+	default:
+		writeError(w, http.StatusNotFound, "unknown path "+r.URL.Path)
+		return
+	}
+	obj["userCount"] = len(obj["users"].([]interface{}))
+	writeResponse(w, obj)
+}
+
+// inviteUsers handles /user/invite, which creates a list of users
+func (s *Server) inviteUsers(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var users []map[string]interface{}
+	if err := json.Unmarshal(body, &users); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid JSON body: %s", err))
+		return
+	}
+
+	c := s.collections["user"]
+	invited := []map[string]interface{}{}
+	for _, obj := range users {
+		id, err := s.create("user", c, obj)
+		if err != nil {
+			writeError(w, http.StatusConflict, err.Error())
+			return
+		}
+		invited = append(invited, c.live[id])
+	}
+	writeResponse(w, invited)
+}
+
+// acl handles the /{type}/acl endpoints, which read and write the access
+// control lists of a batch of entities. ACLs are kept in the acl property of
+// each entity, as Wavefront returns for dashboards.
+func (s *Server) acl(w http.ResponseWriter, r *http.Request, entityType string, parts []string) {
+	c := s.collections[entityType]
+
+	if len(parts) == 0 && r.Method == "GET" {
+		acls := []map[string]interface{}{}
+		for _, id := range r.URL.Query()["id"] {
+			obj := c.live[id]
+			if obj == nil {
+				writeError(w, http.StatusNotFound, fmt.Sprintf("%s %s does not exist", entityType, id))
+				return
+			}
+			acl, _ := obj["acl"].(map[string]interface{})
+			acls = append(acls, map[string]interface{}{
+				"entityId":  id,
+				"viewAcl":   s.principals(acl["canView"]),
+				"modifyAcl": s.principals(acl["canModify"]),
+			})
+		}
+		writeResponse(w, acls)
+		return
+	}
+
+	if len(parts) != 1 || !(parts[0] == "set" && r.Method == "PUT" ||
+		(parts[0] == "add" || parts[0] == "remove") && r.Method == "POST") {
+		writeError(w, http.StatusNotFound, "unknown path "+r.URL.Path)
+		return
+	}
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var acls []struct {
+		EntityID string   `json:"entityId"`
+		View     []string `json:"viewAcl"`
+		Modify   []string `json:"modifyAcl"`
+	}
+	if err := json.Unmarshal(body, &acls); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid JSON body: %s", err))
+		return
+	}
+	for _, acl := range acls {
+		if c.live[acl.EntityID] == nil {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("%s %s does not exist", entityType, acl.EntityID))
+			return
+		}
+	}
+
+	for _, acl := range acls {
+		obj := c.live[acl.EntityID]
+		current, _ := obj["acl"].(map[string]interface{})
+		if current == nil || parts[0] == "set" {
+			current = map[string]interface{}{"canView": []interface{}{}, "canModify": []interface{}{}}
+		}
+		if parts[0] == "remove" {
+			current["canView"] = removeStrings(current["canView"], acl.View)
+			current["canModify"] = removeStrings(current["canModify"], acl.Modify)
+		} else {
+			current["canView"] = addStrings(current["canView"], acl.View)
+			current["canModify"] = addStrings(current["canModify"], acl.Modify)
+		}
+		obj["acl"] = current
+	}
+	writeResponse(w, nil)
+}
+
+// principals returns the IDs in ids along with the names of the accounts,
+// groups or roles they identify
+func (s *Server) principals(ids interface{}) []map[string]interface{} {
+	out := []map[string]interface{}{}
+	list, _ := ids.([]interface{})
+	for _, v := range list {
+		id, _ := v.(string)
+		name := id
+		for _, entityType := range []string{"usergroup", "role"} {
+			if obj := s.collections[entityType].live[id]; obj != nil {
+				name, _ = obj["name"].(string)
+			}
+		}
+		out = append(out, map[string]interface{}{"id": id, "name": name})
+	}
+	return out
+}
+
+// deleteAccount handles /account/{id}, which deletes a user or service account
+func (s *Server) deleteAccount(w http.ResponseWriter, id string) {
+	for _, entityType := range []string{"serviceaccount", "user"} {
+		c := s.collections[entityType]
+		if obj, ok := c.live[id]; ok {
+			delete(c.live, id)
+			c.remove(id)
+			writeResponse(w, obj)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, fmt.Sprintf("account %s does not exist", id))
+}
+
+// apiTokens handles the /apitoken endpoints, for the tokens of the calling
+// account and, under /apitoken/serviceaccount/{id}, of service accounts
+func (s *Server) apiTokens(w http.ResponseWriter, r *http.Request, parts []string) {
+	holder, account, accountType := s.account, "wavefronttest", wavefront.APITokenUserAccount
+	if len(parts) > 0 && parts[0] == "serviceaccount" {
+		if len(parts) < 2 {
+			writeError(w, http.StatusNotFound, "unknown path "+r.URL.Path)
+			return
+		}
+		account, accountType = parts[1], wavefront.APITokenServiceAccount
+		holder = s.collections["serviceaccount"].live[account]
+		if holder == nil {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("serviceaccount %s does not exist", account))
+			return
+		}
+		parts = parts[2:]
+	}
+	tokens, _ := holder["tokens"].([]interface{})
+
+	switch {
+	case len(parts) == 0 && r.Method == "GET":
+		writeResponse(w, tokens)
+
+	case len(parts) == 0 && r.Method == "POST":
+		name := ""
+		if body, _ := ioutil.ReadAll(r.Body); len(body) > 0 {
+			token := &wavefront.APIToken{}
+			if err := json.Unmarshal(body, token); err != nil {
+				writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid JSON body: %s", err))
+				return
+			}
+			name = token.Name
+		}
+		tokens = append(tokens, map[string]interface{}{
+			"tokenID":       fmt.Sprintf("wavefronttest-token-%d", s.nextID),
+			"tokenName":     name,
+			"account":       account,
+			"accountType":   accountType,
+			"dateGenerated": nowMillis(),
+		})
+		s.nextID++
+		holder["tokens"] = tokens
+		writeResponse(w, tokens)
+
+	case len(parts) == 1 && (r.Method == "PUT" || r.Method == "DELETE"):
+		for i, t := range tokens {
+			token := t.(map[string]interface{})
+			if token["tokenID"] != parts[0] {
+				continue
+			}
+			if r.Method == "DELETE" {
+				holder["tokens"] = append(tokens[:i:i], tokens[i+1:]...)
+				writeResponse(w, token)
+				return
+			}
+			update, err := readObject(r)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			token["tokenName"] = update["tokenName"]
+			writeResponse(w, token)
+			return
+		}
+		writeError(w, http.StatusNotFound, fmt.Sprintf("token %s does not exist", parts[0]))
+
+	default:
+		writeError(w, http.StatusNotFound, "unknown path "+r.URL.Path)
+	}
+}
+
+// rolePermission handles /role/grant/{permission} and
+// /role/revoke/{permission}, which change the permissions of a list of roles
+func (s *Server) rolePermission(w http.ResponseWriter, r *http.Request, action, permission string) {
+	ids, err := readStrings(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c := s.collections["role"]
+	for _, id := range ids {
+		if c.live[id] == nil {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("role %s does not exist", id))
+			return
+		}
+	}
+	for _, id := range ids {
+		obj := c.live[id]
+		if action == "grant" {
+			obj["permissions"] = addStrings(obj["permissions"], []string{permission})
+		} else {
+			obj["permissions"] = removeStrings(obj["permissions"], []string{permission})
+		}
+	}
+	writeResponse(w, nil)
+}
+
+// create stores a new entity, assigning it an ID if needed
+func (s *Server) create(entityType string, c *collection, obj map[string]interface{}) (string, error) {
+	normalise(entityType, obj)
+	id, _ := obj["id"].(string)
+	if id == "" && entityType == "dashboard" {
+		// dashboards are identified by their URL
+		id, _ = obj["url"].(string)
+	}
+	if entityType == "user" || entityType == "serviceaccount" {
+		// accounts are identified by their identifier, e.g. an email address
+		id, _ = obj["identifier"].(string)
+		if id == "" {
+			return "", fmt.Errorf("%s identifier is required", entityType)
+		}
+	}
+	if id == "" {
+		id = strconv.Itoa(s.nextID)
+		s.nextID++
+	}
+	if c.live[id] != nil || c.deleted[id] != nil {
+		return "", fmt.Errorf("%s %s already exists", entityType, id)
+	}
+
+	obj["id"] = id
+	obj["createdEpochMillis"] = nowMillis()
+	obj["updatedEpochMillis"] = nowMillis()
+	c.live[id] = obj
+	c.ids = append(c.ids, id)
+	c.record(entityType, id, obj, "Created")
+	return id, nil
+}
+
+// normalise converts the shape of an entity written by a client into the
+// shape returned by Wavefront, where the two differ
+func normalise(entityType string, obj map[string]interface{}) {
+	if entityType == "usergroup" {
+		if roles, ok := obj["roleIDs"]; ok {
+			obj["roles"] = roles
+			delete(obj, "roleIDs")
+		}
+		if obj["users"] == nil {
+			obj["users"] = []interface{}{}
+		}
+	}
+	if entityType == "serviceaccount" {
+		// tokens are generated through the apitoken endpoints
+		obj["tokens"] = []interface{}{}
+	}
+}
+
+// record adds a copy of obj to the version history of entity id
+func (c *collection) record(entityType, id string, obj map[string]interface{}, description string) {
+	if !historyTypes[entityType] {
+		return
+	}
+	c.history[id] = append(c.history[id], &revision{
+		entry: wavefront.HistoryEntry{
+			ID:                id,
+			Version:           int64(len(c.history[id]) + 1),
+			UpdateUser:        "wavefronttest",
+			UpdateTime:        nowMillis(),
+			ChangeDescription: []string{description},
+		},
+		obj: copyObject(obj),
+	})
+}
+
+// revision returns the given version of entity id, or nil
+func (c *collection) revision(id, version string) *revision {
+	v, err := strconv.Atoi(version)
+	if err != nil || v < 1 || v > len(c.history[id]) {
+		return nil
+	}
+	return c.history[id][v-1]
+}
+
+// isVersion reports whether part of a path is a version number
+func isVersion(part string) bool {
+	_, err := strconv.Atoi(part)
+	return err == nil
+}
+
+// listHistory writes a page of history, newest first
+func (s *Server) listHistory(w http.ResponseWriter, r *http.Request, history []*revision) {
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit <= 0 {
+		limit = 100
+	}
+
+	var items []wavefront.HistoryEntry
+	for i := len(history) - 1 - offset; i >= 0 && len(items) < limit; i-- {
+		items = append(items, history[i].entry)
+	}
+	writeResponse(w, map[string]interface{}{
+		"items":     items,
+		"offset":    offset,
+		"limit":     limit,
+		"moreItems": offset+len(items) < len(history),
+	})
+}
+
+// remove drops id from the creation order of the collection
+func (c *collection) remove(id string) {
+	for i, v := range c.ids {
+		if v == id {
+			c.ids = append(c.ids[:i], c.ids[i+1:]...)
+			return
+		}
+	}
+}
+
+// search handles /search/{type} and /search/{type}/deleted
+func (s *Server) search(w http.ResponseWriter, r *http.Request, entityType string, deleted bool) {
+	c, ok := s.collections[entityType]
+	if !ok || r.Method != "POST" {
+		writeError(w, http.StatusNotFound, "unknown path "+r.URL.Path)
+		return
+	}
+
+	params := &wavefront.SearchParams{}
+	body, err := ioutil.ReadAll(r.Body)
+	if err == nil {
+		err = json.Unmarshal(body, params)
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid search: "+err.Error())
+		return
+	}
+	if params.Limit <= 0 {
+		params.Limit = 100
+	}
+
+	store := c.live
+	if deleted {
+		store = c.deleted
+	}
+	var matches []map[string]interface{}
+	for _, id := range c.ids {
+		obj, ok := store[id]
+		if !ok || !matchesAll(obj, params) {
+			continue
+		}
+		matches = append(matches, obj)
+	}
+
+	items := []map[string]interface{}{}
+	if params.Offset < len(matches) {
+		end := params.Offset + params.Limit
+		if end > len(matches) {
+			end = len(matches)
+		}
+		items = matches[params.Offset:end]
+	}
+
+	writeResponse(w, map[string]interface{}{
+		"items":     items,
+		"offset":    params.Offset,
+		"limit":     params.Limit,
+		"moreItems": params.Offset+params.Limit < len(matches),
+	})
+}
+
+// query handles the chart API
+func (s *Server) query(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query().Get("q")
+	resp, ok := s.queries[q]
+	if !ok {
+		resp = &wavefront.QueryResponse{Query: q, TimeSeries: []wavefront.TimeSeries{}}
+	}
+	// RawResponse is populated by the client, it is not part of the API
+	b, _ := json.Marshal(resp)
+	obj := map[string]interface{}{}
+	json.Unmarshal(b, &obj)
+	delete(obj, "RawResponse")
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(obj)
+}
+
+// matchesAll reports whether obj satisfies every condition and time range
+// in params
+func matchesAll(obj map[string]interface{}, params *wavefront.SearchParams) bool {
+	for _, cond := range params.Conditions {
+		if !matches(fieldValues(obj, cond.Key), cond) {
+			return false
+		}
+	}
+	if tr := params.TimeRange; tr != nil {
+		start, _ := obj["startTime"].(float64)
+		if int64(start) < tr.StartTime || int64(start) > tr.EndTime {
+			return false
+		}
+	}
+	return true
+}
+
+// fieldValues returns the string values of a field of obj. Tags are read from
+// either a plain list, the customerTags of a tags object, or the tags set in a
+// map of source tags.
+func fieldValues(obj map[string]interface{}, key string) []string {
+	v := obj[key]
+	if key == "tags" || key == "tagpath" {
+		v = obj["tags"]
+		if m, ok := v.(map[string]interface{}); ok {
+			if _, ok := m["customerTags"]; ok {
+				v = m["customerTags"]
+			} else {
+				var tags []string
+				for tag, set := range m {
+					if set == true {
+						tags = append(tags, tag)
+					}
+				}
+				return tags
+			}
+		}
+	}
+	switch t := v.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		var out []string
+		for _, e := range t {
+			out = append(out, fmt.Sprint(e))
+		}
+		return out
+	case float64:
+		return []string{strconv.FormatFloat(t, 'f', -1, 64)}
+	default:
+		return []string{fmt.Sprint(t)}
+	}
+}
+
+// matches reports whether any of values satisfies cond
+func matches(values []string, cond *wavefront.SearchCondition) bool {
+	for _, v := range values {
+		switch strings.ToUpper(cond.MatchingMethod) {
+		case "CONTAINS", "":
+			if strings.Contains(strings.ToLower(v), strings.ToLower(cond.Value)) {
+				return true
+			}
+		case "STARTSWITH":
+			if strings.HasPrefix(strings.ToLower(v), strings.ToLower(cond.Value)) {
+				return true
+			}
+		case "EXACT":
+			if v == cond.Value {
+				return true
+			}
+		case "TAGPATH":
+			// a tag path matches the tag itself and any tag below it
+			// in the dot-separated hierarchy
+			path := strings.TrimSuffix(strings.TrimSuffix(cond.Value, "*"), ".")
+			if v == path || strings.HasPrefix(v, path+".") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// copyObject returns a deep copy of obj
+func copyObject(obj map[string]interface{}) map[string]interface{} {
+	b, _ := json.Marshal(obj)
+	c := map[string]interface{}{}
+	json.Unmarshal(b, &c)
+	return c
+}
+
+// readObject decodes a JSON object from the request body
+func readObject(r *http.Request) (map[string]interface{}, error) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	obj := map[string]interface{}{}
+	if err := json.Unmarshal(body, &obj); err != nil {
+		return nil, fmt.Errorf("invalid JSON body: %s", err)
+	}
+	return obj, nil
+}
+
+// readStrings decodes a JSON list of strings from the request body
+func readStrings(r *http.Request) ([]string, error) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	var values []string
+	if err := json.Unmarshal(body, &values); err != nil {
+		return nil, fmt.Errorf("invalid JSON body: %s", err)
+	}
+	return values, nil
+}
+
+// addStrings returns list with any of values it does not already contain
+// appended
+func addStrings(list interface{}, values []string) []interface{} {
+	existing, _ := list.([]interface{})
+	out := append([]interface{}{}, existing...)
+	for _, v := range values {
+		found := false
+		for _, e := range out {
+			if e == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// removeStrings returns list without any of values
+func removeStrings(list interface{}, values []string) []interface{} {
+	out := []interface{}{}
+	existing, _ := list.([]interface{})
+	for _, e := range existing {
+		keep := true
+		for _, v := range values {
+			if e == v {
+				keep = false
+				break
+			}
+		}
+		if keep {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+func writeResponse(w http.ResponseWriter, response interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status": map[string]interface{}{
+			"result": "OK",
+			"code":   http.StatusOK,
+		},
+		"response": response,
+	})
+}
+
+func writeError(w http.ResponseWriter, code int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status": map[string]interface{}{
+			"result":  "ERROR",
+			"message": message,
+			"code":    code,
+		},
+	})
+}
+
+func nowMillis() int64 {
+	return time.Now().UnixNano() / int64(time.Millisecond)
+}
+
+// sortedKeys is used to give deterministic error messages
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,724 @@
+package wavefronttest
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	wavefront "github.com/spaceapegames/go-wavefront"
+)
+
+func newTestClient(t *testing.T) (*Server, *wavefront.Client) {
+	srv := NewServer()
+	client, err := wavefront.NewClient(srv.ClientConfig())
+	if err != nil {
+		srv.Close()
+		t.Fatal("error initiating client:", err)
+	}
+	return srv, client
+}
+
+func TestServer_AlertCRUD(t *testing.T) {
+	srv, client := newTestClient(t)
+	defer srv.Close()
+	alerts := client.Alerts()
+
+	alert := &wavefront.Alert{
+		Name:      "test alert",
+		Condition: "ts(servers.cpu.usage) > 10",
+		Minutes:   2,
+		Tags:      []string{"team.db", "dc1"},
+	}
+	if err := alerts.Create(alert); err != nil {
+		t.Fatal(err)
+	}
+	if alert.ID == nil || *alert.ID == "" {
+		t.Fatal("expected alert ID to be assigned")
+	}
+
+	fetched := &wavefront.Alert{ID: alert.ID}
+	if err := alerts.Get(fetched); err != nil {
+		t.Fatal(err)
+	}
+	if fetched.Name != "test alert" || len(fetched.Tags) != 2 {
+		t.Errorf("unexpected alert from Get: %+v", fetched)
+	}
+
+	fetched.Minutes = 5
+	if err := alerts.Update(fetched); err != nil {
+		t.Fatal(err)
+	}
+
+	id := *alert.ID
+	if err := alerts.Delete(alert); err != nil {
+		t.Fatal(err)
+	}
+
+	// deleted alerts move to the trash
+	deleted, err := client.NewSearch("alert", &wavefront.SearchParams{}).Execute()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(deleted.Response.Items) != "[]" {
+		t.Errorf("expected no live alerts after delete, got %s", deleted.Response.Items)
+	}
+	search := client.NewSearch("alert", &wavefront.SearchParams{})
+	search.Deleted = true
+	trash, err := search.Execute()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(trash.Response.Items) == "[]" {
+		t.Error("expected deleted alert in the trash")
+	}
+
+	// deleting from the trash removes the alert permanently
+	if err := alerts.Delete(&wavefront.Alert{ID: &id}); err != nil {
+		t.Fatal(err)
+	}
+	if err := alerts.Get(&wavefront.Alert{ID: &id}); !wavefront.IsNotFound(err) {
+		t.Errorf("expected not found after permanent delete, got %v", err)
+	}
+}
+
+func TestServer_SearchPagination(t *testing.T) {
+	srv, client := newTestClient(t)
+	defer srv.Close()
+
+	for i := 0; i < 150; i++ {
+		tag := "web"
+		if i%3 == 0 {
+			tag = "db.primary"
+		}
+		srv.Seed("alert", &wavefront.Alert{
+			Name: fmt.Sprintf("alert %d", i),
+			Tags: []string{tag},
+		})
+	}
+
+	all, err := client.Alerts().Find(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 150 {
+		t.Errorf("paginated find, expected 150 alerts, got %d", len(all))
+	}
+
+	tests := []struct {
+		cond     *wavefront.SearchCondition
+		expected int
+	}{
+		{&wavefront.SearchCondition{Key: "tags", Value: "db", MatchingMethod: "TAGPATH"}, 50},
+		{&wavefront.SearchCondition{Key: "tags", Value: "db.primary", MatchingMethod: "EXACT"}, 50},
+		{&wavefront.SearchCondition{Key: "tags", Value: "db", MatchingMethod: "EXACT"}, 0},
+		{&wavefront.SearchCondition{Key: "name", Value: "alert 1", MatchingMethod: "STARTSWITH"}, 61},
+		{&wavefront.SearchCondition{Key: "name", Value: "14", MatchingMethod: "CONTAINS"}, 12},
+	}
+	for _, test := range tests {
+		found, err := client.Alerts().Find([]*wavefront.SearchCondition{test.cond})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(found) != test.expected {
+			t.Errorf("%s %s %s, expected %d alerts, got %d",
+				test.cond.Key, test.cond.MatchingMethod, test.cond.Value, test.expected, len(found))
+		}
+	}
+}
+
+func TestServer_AlertSnooze(t *testing.T) {
+	srv, client := newTestClient(t)
+	defer srv.Close()
+	alerts := client.Alerts()
+
+	for i := 0; i < 5; i++ {
+		tag := "web"
+		if i%2 == 0 {
+			tag = "db"
+		}
+		srv.Seed("alert", &wavefront.Alert{Name: fmt.Sprintf("alert %d", i), Tags: []string{tag}})
+	}
+
+	results, err := alerts.SnoozeMatching([]*wavefront.SearchCondition{
+		{Key: "tags", Value: "db", MatchingMethod: "EXACT"},
+	}, 3600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+	for _, r := range results {
+		if r.Err != nil {
+			t.Errorf("%s: %s", r.Alert.Name, r.Err)
+		}
+		if r.Alert.Snoozed <= 0 {
+			t.Errorf("%s: expected snoozed to be set, got %d", r.Alert.Name, r.Alert.Snoozed)
+		}
+	}
+
+	alert := results[0].Alert
+	if err := alerts.Unsnooze(alert); err != nil {
+		t.Fatal(err)
+	}
+	if alert.Snoozed != 0 {
+		t.Errorf("expected snoozed to be cleared, got %d", alert.Snoozed)
+	}
+	if err := alerts.Snooze(alert, 0); err != nil {
+		t.Fatal(err)
+	}
+	if alert.Snoozed != -1 {
+		t.Errorf("expected an indefinite snooze, got %d", alert.Snoozed)
+	}
+
+	missing := "missing"
+	if err := alerts.Snooze(&wavefront.Alert{ID: &missing}, 60); !wavefront.IsNotFound(err) {
+		t.Errorf("expected not found snoozing a missing alert, got %v", err)
+	}
+}
+
+func TestServer_History(t *testing.T) {
+	srv, client := newTestClient(t)
+	defer srv.Close()
+	alerts := client.Alerts()
+
+	alert := &wavefront.Alert{Name: "v1", Condition: "ts(a) > 1", Minutes: 2}
+	if err := alerts.Create(alert); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"v2", "v3"} {
+		alert.Name = name
+		if err := alerts.Update(alert); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	history, err := alerts.History(alert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 3 || history[0].Version != 3 {
+		t.Fatalf("expected 3 versions, newest first, got %+v", history)
+	}
+
+	old := &wavefront.Alert{ID: alert.ID}
+	if err := alerts.GetVersion(old, 1); err != nil {
+		t.Fatal(err)
+	}
+	if old.Name != "v1" {
+		t.Errorf("expected version 1 to be named v1, got %s", old.Name)
+	}
+
+	if err := alerts.Revert(alert, 1); err != nil {
+		t.Fatal(err)
+	}
+	if alert.Name != "v1" {
+		t.Errorf("expected reverted alert to be named v1, got %s", alert.Name)
+	}
+	history, _ = alerts.History(alert)
+	if len(history) != 4 {
+		t.Errorf("expected revert to add a version, got %d", len(history))
+	}
+
+	if err := alerts.GetVersion(alert, 9); !wavefront.IsNotFound(err) {
+		t.Errorf("expected not found for a missing version, got %v", err)
+	}
+
+	dashboards := client.Dashboards()
+	dashboard := &wavefront.Dashboard{Name: "before", Url: "history-test"}
+	if err := dashboards.Create(dashboard); err != nil {
+		t.Fatal(err)
+	}
+	dashboard.Name = "after"
+	if err := dashboards.Update(dashboard); err != nil {
+		t.Fatal(err)
+	}
+	if err := dashboards.Revert(dashboard, 1); err != nil {
+		t.Fatal(err)
+	}
+	if dashboard.Name != "before" {
+		t.Errorf("expected reverted dashboard to be named before, got %s", dashboard.Name)
+	}
+}
+
+func TestServer_Trash(t *testing.T) {
+	srv, client := newTestClient(t)
+	defer srv.Close()
+	alerts := client.Alerts()
+
+	alert := &wavefront.Alert{Name: "trash me", Condition: "ts(a) > 1", Minutes: 2}
+	if err := alerts.Create(alert); err != nil {
+		t.Fatal(err)
+	}
+	if err := alerts.Trash(alert); err != nil {
+		t.Fatal(err)
+	}
+	if alert.ID == nil || !alert.Deleted {
+		t.Fatalf("expected a deleted alert with its ID kept, got %+v", alert)
+	}
+
+	deleted, err := alerts.FindDeleted(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deleted) != 1 || deleted[0].Name != "trash me" {
+		t.Fatalf("expected the alert in the trash, got %d alerts", len(deleted))
+	}
+
+	if err := alerts.Undelete(deleted[0]); err != nil {
+		t.Fatal(err)
+	}
+	if deleted[0].Deleted {
+		t.Error("expected undeleted alert not to be marked deleted")
+	}
+	live, _ := alerts.Find(nil)
+	if len(live) != 1 {
+		t.Errorf("expected the alert to be live again, got %d live alerts", len(live))
+	}
+
+	id := *alert.ID
+	if err := alerts.DeletePermanently(alert); err != nil {
+		t.Fatal(err)
+	}
+	if err := alerts.Get(&wavefront.Alert{ID: &id}); !wavefront.IsNotFound(err) {
+		t.Errorf("expected not found after permanent delete, got %v", err)
+	}
+	if deleted, _ := alerts.FindDeleted(nil); len(deleted) != 0 {
+		t.Errorf("expected nothing in the trash, got %d alerts", len(deleted))
+	}
+
+	dashboards := client.Dashboards()
+	dashboard := &wavefront.Dashboard{Name: "trash me", Url: "trash-me"}
+	if err := dashboards.Create(dashboard); err != nil {
+		t.Fatal(err)
+	}
+	if err := dashboards.Trash(dashboard); err != nil {
+		t.Fatal(err)
+	}
+	if err := dashboards.Undelete(dashboard); err != nil {
+		t.Fatal(err)
+	}
+	if err := dashboards.Undelete(dashboard); err == nil {
+		t.Error("expected undeleting a live dashboard to fail")
+	}
+}
+
+func TestServer_CloudIntegrations(t *testing.T) {
+	srv, client := newTestClient(t)
+	defer srv.Close()
+	integrations := client.CloudIntegrations()
+
+	integration := &wavefront.CloudIntegration{
+		Name:    "gcp",
+		Service: wavefront.CloudIntegrationGCP,
+		GCP:     &wavefront.GCPConfiguration{ProjectID: "project"},
+	}
+	if err := integrations.Create(integration); err != nil {
+		t.Fatal(err)
+	}
+	if err := integrations.Disable(integration); err != nil {
+		t.Fatal(err)
+	}
+	if !integration.Disabled {
+		t.Error("expected the integration to be disabled")
+	}
+	if err := integrations.Enable(integration); err != nil {
+		t.Fatal(err)
+	}
+	if integration.Disabled || integration.GCP == nil || integration.GCP.ProjectID != "project" {
+		t.Errorf("expected an enabled GCP integration, got %+v", integration)
+	}
+
+	if err := integrations.Trash(integration); err != nil {
+		t.Fatal(err)
+	}
+	if deleted, _ := integrations.FindDeleted(nil); len(deleted) != 1 {
+		t.Errorf("expected 1 integration in the trash, got %d", len(deleted))
+	}
+	if err := integrations.Undelete(integration); err != nil {
+		t.Fatal(err)
+	}
+	if live, _ := integrations.Find(nil); len(live) != 1 {
+		t.Errorf("expected 1 live integration, got %d", len(live))
+	}
+}
+
+func TestServer_AccountManagement(t *testing.T) {
+	srv, client := newTestClient(t)
+	defer srv.Close()
+
+	role := &wavefront.Role{Name: "operators", Permissions: []string{"alerts_management"}}
+	if err := client.Roles().Create(role); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Roles().GrantPermission("events_management", *role.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Roles().Get(role); err != nil {
+		t.Fatal(err)
+	}
+	if len(role.Permissions) != 2 {
+		t.Errorf("expected 2 permissions after grant, got %v", role.Permissions)
+	}
+
+	email := "someone@example.com"
+	user := &wavefront.User{ID: &email, Permissions: []string{"dashboard_management"}}
+	if err := client.Users().Invite(user); err != nil {
+		t.Fatal(err)
+	}
+	if users, _ := client.Users().Find(nil); len(users) != 1 || *users[0].ID != email {
+		t.Errorf("expected the invited user to be found, got %+v", users)
+	}
+
+	group := &wavefront.UserGroup{Name: "on-call", Roles: []string{*role.ID}}
+	if err := client.UserGroups().Create(group); err != nil {
+		t.Fatal(err)
+	}
+	if len(group.Roles) != 1 || group.Roles[0] != *role.ID {
+		t.Errorf("expected the group to have role %s, got %v", *role.ID, group.Roles)
+	}
+	if err := client.UserGroups().AddUsers(group, email); err != nil {
+		t.Fatal(err)
+	}
+	group.Description = "paged out of hours"
+	if err := client.UserGroups().Update(group); err != nil {
+		t.Fatal(err)
+	}
+	if len(group.Users) != 1 || group.UserCount != 1 {
+		t.Errorf("expected the group to keep its user on update, got %+v", group)
+	}
+	if err := client.UserGroups().RemoveRoles(group, *role.ID); err != nil {
+		t.Fatal(err)
+	}
+	if len(group.Roles) != 0 {
+		t.Errorf("expected no roles after removal, got %v", group.Roles)
+	}
+
+	if err := client.Users().Delete(user); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Users().Get(&wavefront.User{ID: &email}); !wavefront.IsNotFound(err) {
+		t.Errorf("expected not found after delete, got %v", err)
+	}
+}
+
+func TestServer_ServiceAccountTokens(t *testing.T) {
+	srv, client := newTestClient(t)
+	defer srv.Close()
+	accounts, tokens := client.ServiceAccounts(), client.APITokens()
+
+	id := "sa::ci"
+	account := &wavefront.ServiceAccount{ID: &id, Active: true}
+	if err := accounts.Create(account); err != nil {
+		t.Fatal(err)
+	}
+	old, err := tokens.CreateForServiceAccount(id, "deploys")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var stored string
+	token, err := tokens.Rotate(old, func(token *wavefront.APIToken) error {
+		stored = *token.ID
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored == "" || stored == *old.ID || token.Name != "deploys" {
+		t.Errorf("expected a new deploys token to be stored, got %+v", token)
+	}
+	if list, _ := tokens.ListForServiceAccount(id); len(list) != 1 || *list[0].ID != stored {
+		t.Errorf("expected only the new token to remain, got %+v", list)
+	}
+
+	if err := tokens.Rename(token, "releases"); err != nil {
+		t.Fatal(err)
+	}
+	if err := accounts.Deactivate(account); err != nil {
+		t.Fatal(err)
+	}
+	if account.Active || len(account.Tokens) != 1 || account.Tokens[0].Name != "releases" {
+		t.Errorf("expected an inactive account with the renamed token, got %+v", account)
+	}
+
+	// updates keep the tokens
+	account.Description = "continuous integration"
+	if err := accounts.Update(account); err != nil {
+		t.Fatal(err)
+	}
+	if len(account.Tokens) != 1 {
+		t.Errorf("expected the token to be kept on update, got %+v", account.Tokens)
+	}
+
+	if err := accounts.Delete(account); err != nil {
+		t.Fatal(err)
+	}
+	if found, _ := accounts.Find(nil); len(found) != 0 {
+		t.Errorf("expected no service accounts after delete, got %d", len(found))
+	}
+}
+
+func TestServer_ACL(t *testing.T) {
+	srv, client := newTestClient(t)
+	defer srv.Close()
+	dashboards := client.Dashboards()
+
+	team := &wavefront.UserGroup{Name: "team"}
+	if err := client.UserGroups().Create(team); err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, url := range []string{"team-one", "team-two"} {
+		if err := dashboards.Create(&wavefront.Dashboard{Name: url, ID: url}); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, url)
+	}
+
+	everyone := wavefront.ACLPrincipal{ID: "everyone"}
+	var acls []*wavefront.ACL
+	for _, id := range ids {
+		acls = append(acls, &wavefront.ACL{
+			EntityID: id,
+			View:     []wavefront.ACLPrincipal{everyone},
+			Modify:   []wavefront.ACLPrincipal{everyone},
+		})
+	}
+	if err := dashboards.SetACL(acls...); err != nil {
+		t.Fatal(err)
+	}
+
+	// restrict modification to the team
+	for _, acl := range acls {
+		acl.View = nil
+		acl.Modify = []wavefront.ACLPrincipal{{ID: *team.ID}}
+	}
+	if err := dashboards.AddACL(acls...); err != nil {
+		t.Fatal(err)
+	}
+	for _, acl := range acls {
+		acl.Modify = []wavefront.ACLPrincipal{everyone}
+	}
+	if err := dashboards.RemoveACL(acls...); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := dashboards.GetACL(ids...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 ACLs, got %d", len(got))
+	}
+	for _, acl := range got {
+		if len(acl.View) != 1 || acl.View[0].ID != "everyone" {
+			t.Errorf("%s: expected everyone to view, got %+v", acl.EntityID, acl.View)
+		}
+		if len(acl.Modify) != 1 || acl.Modify[0].ID != *team.ID || acl.Modify[0].Name != "team" {
+			t.Errorf("%s: expected only the team to modify, got %+v", acl.EntityID, acl.Modify)
+		}
+	}
+
+	if _, err := client.Alerts().GetACL("missing"); !wavefront.IsNotFound(err) {
+		t.Errorf("expected not found for a missing alert, got %v", err)
+	}
+}
+
+func TestServer_Tags(t *testing.T) {
+	srv, client := newTestClient(t)
+	defer srv.Close()
+	alerts := client.Alerts()
+
+	for i := 0; i < 5; i++ {
+		alert := &wavefront.Alert{Name: fmt.Sprintf("alert %d", i), Tags: []string{"team.db"}}
+		if i == 4 {
+			alert.Tags = []string{"team.web"}
+		}
+		if err := alerts.Create(alert); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	filter := []*wavefront.SearchCondition{{Key: "tags", Value: "team.db", MatchingMethod: "EXACT"}}
+	change := wavefront.TagChange{Add: []string{"team.storage"}, Remove: []string{"team.db"}}
+	results, err := client.Tags().ChangeMatching("alert", filter, change, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 4 {
+		t.Fatalf("expected 4 alerts to be changed, got %d", len(results))
+	}
+	for _, result := range results {
+		if result.Err != nil {
+			t.Errorf("%s: %s", result.ID, result.Err)
+		}
+	}
+
+	found, _ := alerts.Find([]*wavefront.SearchCondition{{Key: "tags", Value: "team.storage", MatchingMethod: "EXACT"}})
+	if len(found) != 4 {
+		t.Errorf("expected 4 alerts tagged team.storage, got %d", len(found))
+	}
+	for _, alert := range found {
+		if len(alert.Tags) != 1 {
+			t.Errorf("expected only the team.storage tag, got %v", alert.Tags)
+		}
+	}
+
+	event := &wavefront.Event{Name: "deploy", StartTime: 1600000000000, Tags: []string{"deploy"}}
+	if err := client.Events().Create(event); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Events().AddTag(event, "web"); err != nil {
+		t.Fatal(err)
+	}
+	fetched, err := client.Events().FindByID(*event.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fetched.Tags) != 2 || fetched.Tags[1] != "web" {
+		t.Errorf("expected tags [deploy web], got %v", fetched.Tags)
+	}
+}
+
+func TestServer_Sources(t *testing.T) {
+	srv, client := newTestClient(t)
+	defer srv.Close()
+	sources := client.Sources()
+
+	for i := 0; i < 5; i++ {
+		id := fmt.Sprintf("web-%d", i)
+		if _, err := srv.Seed("source", &wavefront.MetricSource{ID: &id, Tags: []string{"dc1"}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	page, cursor, err := sources.ListPage("", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page) != 2 || cursor != "web-2" {
+		t.Errorf("expected 2 sources and cursor web-2, got %d and %q", len(page), cursor)
+	}
+	all, err := sources.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 5 {
+		t.Fatalf("expected 5 sources, got %d", len(all))
+	}
+
+	web := all[3]
+	if err := sources.AddTag(web, "role.web"); err != nil {
+		t.Fatal(err)
+	}
+	if err := sources.SetDescription(web, "Web server"); err != nil {
+		t.Fatal(err)
+	}
+	found, err := sources.FindByTag("role.web")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 1 || *found[0].ID != "web-3" || found[0].Description != "Web server" {
+		t.Errorf("expected web-3 to be found with its description, got %+v", found)
+	}
+
+	if err := sources.SetTags(web); err != nil {
+		t.Fatal(err)
+	}
+	if found, _ := sources.FindByTag("role.web"); len(found) != 0 {
+		t.Errorf("expected no source tagged role.web, got %+v", found)
+	}
+	if err := sources.RemoveDescription(web); err != nil {
+		t.Fatal(err)
+	}
+	fetched := &wavefront.MetricSource{ID: web.ID}
+	if err := sources.Get(fetched); err != nil {
+		t.Fatal(err)
+	}
+	if len(fetched.Tags) != 0 || fetched.Description != "" {
+		t.Errorf("expected no tags or description, got %+v", fetched)
+	}
+}
+
+func TestServer_Proxies(t *testing.T) {
+	srv, client := newTestClient(t)
+	defer srv.Close()
+	proxies := client.Proxies()
+
+	now := time.Now()
+	for i, checkIn := range []time.Duration{time.Minute, time.Hour, 2 * time.Minute} {
+		proxy := &wavefront.Proxy{
+			Name:            fmt.Sprintf("Proxy on proxy-%d", i),
+			Hostname:        fmt.Sprintf("proxy-%d", i),
+			Status:          wavefront.ProxyActive,
+			LastCheckInTime: now.Add(-checkIn).UnixNano() / int64(time.Millisecond),
+		}
+		if _, err := srv.Seed("proxy", proxy); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stale, err := proxies.FindStale(10 * time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stale) != 1 || stale[0].Hostname != "proxy-1" {
+		t.Fatalf("expected proxy-1 to be stale, got %+v", stale)
+	}
+
+	if err := proxies.Rename(stale[0], "stuck"); err != nil {
+		t.Fatal(err)
+	}
+	if stale[0].Name != "stuck" || stale[0].Hostname != "proxy-1" {
+		t.Errorf("expected only the name to change, got %+v", stale[0])
+	}
+	if err := proxies.Delete(stale[0]); err != nil {
+		t.Fatal(err)
+	}
+	if all, _ := proxies.List(); len(all) != 2 {
+		t.Errorf("expected 2 proxies after delete, got %d", len(all))
+	}
+}
+
+func TestServer_Query(t *testing.T) {
+	srv, client := newTestClient(t)
+	defer srv.Close()
+
+	srv.SetQueryResponse("ts(cpu.load)", &wavefront.QueryResponse{
+		Query: "ts(cpu.load)",
+		TimeSeries: []wavefront.TimeSeries{{
+			Label:      "cpu.load",
+			Host:       "web1",
+			DataPoints: []wavefront.DataPoint{{1500000000, 0.5}},
+		}},
+	})
+
+	resp, err := client.NewQuery(wavefront.NewQueryParams("ts(cpu.load)")).Execute()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.TimeSeries) != 1 || resp.TimeSeries[0].Host != "web1" {
+		t.Errorf("expected seeded time series, got %+v", resp.TimeSeries)
+	}
+
+	resp, err = client.NewQuery(wavefront.NewQueryParams("ts(other)")).Execute()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.TimeSeries) != 0 {
+		t.Errorf("expected no time series for unseeded query, got %d", len(resp.TimeSeries))
+	}
+}
+
+func TestServer_Unauthorized(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	config := srv.ClientConfig()
+	config.Token = "wrong"
+	client, _ := wavefront.NewClient(config)
+	if _, err := client.Dashboards().Find(nil); !wavefront.IsUnauthorized(err) {
+		t.Errorf("expected unauthorized error, got %v", err)
+	}
+}